@@ -19,12 +19,30 @@ func main() {
 		os.Exit(1)
 	}
 
-	iamRolePolicy := iamrolepolicyparsing.IamRolePolicy{}
-	err = iamRolePolicy.UnmarshalJSON(json)
-	if err != nil {
-		fmt.Println("Error parsing file:", err.Error())
+	// the envelope (AWS::IAM::RolePolicy, AWS::IAM::ManagedPolicy...) is detected from the keys of the file
+	envelope, policyDocument, parseErrors := iamrolepolicyparsing.ValidateEnvelope(json)
+	if len(parseErrors) > 0 {
+		// file:line:column: message, the format editors jump to, or file: message for errors without a location
+		for _, parseError := range parseErrors {
+			if parseError.Line == 0 {
+				fmt.Printf("%s: %s\n", os.Args[1], parseError.Error())
+				continue
+			}
+			fmt.Printf("%s:%d:%d: %s\n", os.Args[1], parseError.Line, parseError.Column, parseError.Error())
+		}
 		os.Exit(1)
 	}
+	fmt.Println("Parsed as", envelope)
+
+	println(policyDocument.NoStatementHasWildcardResource())
 
-	println(iamRolePolicy.NoStatementHasWildcardResource())
+	for _, finding := range policyDocument.StatementsWithWildcardResource() {
+		fmt.Println("Wildcard resource in", finding)
+	}
+	for _, finding := range policyDocument.StatementsWithWildcardAction() {
+		fmt.Println("Wildcard action in", finding)
+	}
+	for _, finding := range policyDocument.StatementsWithServiceWideAction() {
+		fmt.Println("Service-wide wildcard action in", finding)
+	}
 }
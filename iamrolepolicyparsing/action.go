@@ -0,0 +1,107 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/**
+ * Action struct represents a single Action or NotAction value of a statement.
+ *
+ * An action "service:Operation" is split into its Service prefix (e.g. "s3") and its Name (e.g. "GetObject").
+ * The Name may contain the '*' and '?' wildcards (e.g. "Get*"). The bare "*" action is represented as
+ * Service "*" and Name "*" (see WildcardAction).
+ *
+ * for grammar see https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_elements_action.html
+ */
+type Action struct {
+	Service string
+	Name    string
+}
+
+/**
+ * WildcardAction is the Action form of the bare "*" action.
+ */
+var WildcardAction = Action{Service: "*", Name: "*"}
+
+func (action Action) String() string {
+	if action == WildcardAction {
+		return "*"
+	}
+	return action.Service + ":" + action.Name
+}
+
+/**
+ * Parses an Action value, which must be "*" or "service:action" with a service prefix made of
+ * letters, digits and hyphens and an action name made of letters and digits.
+ * Wildcards ('*' and '?') may only appear in the action name.
+ *
+ * Like in IAM, the service prefix is case-insensitive ("S3:GetObject" is "s3:GetObject"), it is kept as written.
+ */
+func ParseAction(s string) (Action, error) {
+	if s == "*" {
+		return WildcardAction, nil
+	}
+	segments := strings.Split(s, ":")
+	if len(segments) < 2 {
+		return Action{}, errors.New(fmt.Sprintf(`action "%s" should have the form service:action, it has no colon`, s))
+	}
+	if len(segments) > 2 {
+		return Action{}, errors.New(fmt.Sprintf(`action "%s" should have the form service:action, it has more than one colon`, s))
+	}
+
+	action := Action{Service: segments[0], Name: segments[1]}
+	if containsWildcard(action.Service) {
+		return Action{}, errors.New(fmt.Sprintf(`action "%s" has a wildcard in the service prefix "%s", wildcards are only allowed in the action name`, s, action.Service))
+	}
+	if !isServicePrefix(strings.ToLower(action.Service)) {
+		return Action{}, errors.New(fmt.Sprintf(`action "%s" has an invalid service prefix "%s"`, s, action.Service))
+	}
+	if action.Name == "" {
+		return Action{}, errors.New(fmt.Sprintf(`action "%s" has an empty action name`, s))
+	}
+	if i := strings.IndexFunc(action.Name, func(c rune) bool { return !isActionNameRune(c) }); i != -1 {
+		return Action{}, errors.New(fmt.Sprintf(`action "%s" has an invalid character %q in the action name "%s", it should contain only letters, digits, '*' and '?'`, s, []rune(action.Name[i:])[0], action.Name))
+	}
+	return action, nil
+}
+
+/**
+ * Returns whether the action covers the given action name (e.g. "s3:GetObject"), honoring wildcards.
+ * Like in IAM, the comparison is case-insensitive.
+ */
+func (action Action) Matches(actionName string) bool {
+	if action == WildcardAction {
+		return true
+	}
+	segments := strings.Split(actionName, ":")
+	if len(segments) != 2 {
+		return false
+	}
+	return strings.EqualFold(action.Service, segments[0]) && globMatch(action.Name, segments[1], true)
+}
+
+/**
+ * Returns whether the action covers every action of a single service (e.g. "iam:*").
+ * The bare "*" action covers every service, so it is not service-wide.
+ */
+func (action Action) IsServiceWide() bool {
+	return action != WildcardAction && isOnlyWildcards(action.Name) && strings.Contains(action.Name, "*")
+}
+
+func isServicePrefix(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+func isActionNameRune(c rune) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '*' || c == '?'
+}
@@ -0,0 +1,163 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAction_Parse(t *testing.T) {
+	tests := map[string]Action{
+		"s3:GetObject":        {Service: "s3", Name: "GetObject"},
+		"s3:Get*":             {Service: "s3", Name: "Get*"},
+		"iam:*":               {Service: "iam", Name: "*"},
+		"ec2:Describe?mages":  {Service: "ec2", Name: "Describe?mages"},
+		"execute-api:Invoke":  {Service: "execute-api", Name: "Invoke"},
+		"*":                   WildcardAction,
+		"s3:ListAllMyBuckets": {Service: "s3", Name: "ListAllMyBuckets"},
+		"S3:GetObject":        {Service: "S3", Name: "GetObject"},
+	}
+
+	for s, expected := range tests {
+		action, err := ParseAction(s)
+		if err != nil {
+			t.Errorf("%s: Expected error: <nil>, got: %v", s, err)
+		}
+		if !reflect.DeepEqual(action, expected) {
+			t.Errorf("%s: Expected: %+v, got: %+v", s, expected, action)
+		}
+		if action.String() != s {
+			t.Errorf("Expected: %s, got: %s", s, action.String())
+		}
+	}
+}
+
+func TestAction_ParseMalformed(t *testing.T) {
+	tests := map[string]error{
+		"s3GetObject":     errors.New(`action "s3GetObject" should have the form service:action, it has no colon`),
+		"s3:Get:Object":   errors.New(`action "s3:Get:Object" should have the form service:action, it has more than one colon`),
+		":GetObject":      errors.New(`action ":GetObject" has an invalid service prefix ""`),
+		"s*:GetObject":    errors.New(`action "s*:GetObject" has a wildcard in the service prefix "s*", wildcards are only allowed in the action name`),
+		"*:*":             errors.New(`action "*:*" has a wildcard in the service prefix "*", wildcards are only allowed in the action name`),
+		"s3:":             errors.New(`action "s3:" has an empty action name`),
+		"":                errors.New(`action "" should have the form service:action, it has no colon`),
+		"s 3:GetObject":   errors.New(`action "s 3:GetObject" has an invalid service prefix "s 3"`),
+		"iam:PassRole:x:": errors.New(`action "iam:PassRole:x:" should have the form service:action, it has more than one colon`),
+		"s3:Get Object":   errors.New(`action "s3:Get Object" has an invalid character ' ' in the action name "Get Object", it should contain only letters, digits, '*' and '?'`),
+		"s3:GetObject ":   errors.New(`action "s3:GetObject " has an invalid character ' ' in the action name "GetObject ", it should contain only letters, digits, '*' and '?'`),
+		"s3:Get-Object":   errors.New(`action "s3:Get-Object" has an invalid character '-' in the action name "Get-Object", it should contain only letters, digits, '*' and '?'`),
+		"s3:Get/*":        errors.New(`action "s3:Get/*" has an invalid character '/' in the action name "Get/*", it should contain only letters, digits, '*' and '?'`),
+	}
+
+	for s, expectedErr := range tests {
+		_, err := ParseAction(s)
+		if !reflect.DeepEqual(err, expectedErr) {
+			t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+		}
+	}
+}
+
+func TestAction_Matches(t *testing.T) {
+	tests := []struct {
+		action   string
+		name     string
+		expected bool
+	}{
+		{"s3:GetObject", "s3:GetObject", true},
+		{"s3:GetObject", "s3:getobject", true},
+		{"S3:GetObject", "s3:GetObject", true},
+		{"s3:Get*", "s3:GetObject", true},
+		{"s3:Get*", "s3:GetObjectAcl", true},
+		{"s3:Get*", "s3:PutObject", false},
+		{"s3:Get*", "ec2:GetConsoleOutput", false},
+		{"s3:*Object", "s3:DeleteObject", true},
+		{"s3:?etObject", "s3:GetObject", true},
+		{"iam:*", "iam:PassRole", true},
+		{"*", "iam:PassRole", true},
+		{"s3:GetObject", "GetObject", false},
+	}
+
+	for _, test := range tests {
+		action, err := ParseAction(test.action)
+		if err != nil {
+			t.Fatalf("Expected error: <nil>, got: %v", err)
+		}
+		if action.Matches(test.name) != test.expected {
+			t.Errorf("%s matches %s: Expected: %v, got: %v", test.action, test.name, test.expected, !test.expected)
+		}
+	}
+}
+
+func TestStatement_UnmarshalParsesActions(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":["s3:Get*","iam:PassRole"],"Resource":"*"}`)
+	var stat Statement
+	err := stat.UnmarshalJSON(data)
+
+	expected := []Action{{Service: "s3", Name: "Get*"}, {Service: "iam", Name: "PassRole"}}
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if !reflect.DeepEqual(stat.Actions, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, stat.Actions)
+	}
+}
+
+func TestStatement_UnmarshalMalformedAction(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":["s3:GetObject","s3GetObject"],"Resource":"*"}`)
+	var stat Statement
+	expectedErr := &ParseError{Code: CodeInvalidAction, Path: "/Action/1", Message: `action "s3GetObject" should have the form service:action, it has no colon`, Err: errors.New(`action "s3GetObject" should have the form service:action, it has no colon`), Line: 1, Column: 44}
+
+	err := stat.UnmarshalJSON(data)
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+	}
+}
+
+func TestStatement_MatchesAction(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":["s3:Get*","s3:List*"],"Resource":"*"}`)
+	var stat Statement
+	if err := stat.UnmarshalJSON(data); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+
+	if !stat.MatchesAction("s3:GetObject") {
+		t.Errorf("Expected: true, got: false")
+	}
+	if stat.MatchesAction("s3:PutObject") {
+		t.Errorf("Expected: false, got: true")
+	}
+}
+
+func TestStatement_MatchesActionWhenNotAction(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","NotAction":"iam:*","Resource":"*"}`)
+	var stat Statement
+	if err := stat.UnmarshalJSON(data); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+
+	if !stat.MatchesAction("s3:GetObject") {
+		t.Errorf("Expected: true, got: false")
+	}
+	if stat.MatchesAction("iam:CreateUser") {
+		t.Errorf("Expected: false, got: true")
+	}
+}
+
+func TestAction_IsServiceWide(t *testing.T) {
+	tests := map[string]bool{
+		"iam:*":        true,
+		"s3:**":        true,
+		"*":            false,
+		"s3:Get*":      false,
+		"s3:GetObject": false,
+		"s3:?":         false,
+	}
+
+	for s, expected := range tests {
+		action, _ := ParseAction(s)
+		if action.IsServiceWide() != expected {
+			t.Errorf("%s: Expected: %v, got: %v", s, expected, !expected)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/**
+ * ARN struct represents an Amazon Resource Name used as a Resource value.
+ *
+ * The ARN "arn:partition:service:region:account:resource" is split into its segments. Resource is split further
+ * into ResourceType and ResourceId on the first '/' or ':' (e.g. "role/Admin" or "function:my-function").
+ * If there is no separator, or the ARN is an S3 bucket/object ARN, ResourceType is empty and ResourceId is the whole Resource.
+ *
+ * Any segment may contain the '*' and '?' wildcards and policy variables such as ${aws:username} (see policyvariable.go).
+ * The bare "*" resource is represented as an ARN whose segments are all "*" (see WildcardARN).
+ *
+ * for grammar see https://docs.aws.amazon.com/IAM/latest/UserGuide/reference-arns.html
+ */
+type ARN struct {
+	Partition    string
+	Service      string
+	Region       string
+	Account      string
+	Resource     string
+	ResourceType string
+	ResourceId   string
+}
+
+/**
+ * WildcardARN is the ARN form of the bare "*" resource.
+ */
+var WildcardARN = ARN{Partition: "*", Service: "*", Region: "*", Account: "*", Resource: "*", ResourceId: "*"}
+
+func (arn ARN) String() string {
+	if arn == WildcardARN {
+		return "*"
+	}
+	return strings.Join([]string{"arn", arn.Partition, arn.Service, arn.Region, arn.Account, arn.Resource}, ":")
+}
+
+/**
+ * Parses a Resource value into an ARN.
+ *
+ * The value must be "*" or "arn:partition:service:region:account:resource" where partition, service and resource
+ * are non-empty and account is empty, a 12-digit account ID, "aws" (AWS managed resources) or contains a wildcard
+ * or a policy variable. Colons inside policy variables don't separate segments.
+ */
+func ParseARN(s string) (ARN, error) {
+	if s == "*" {
+		return WildcardARN, nil
+	}
+	if !strings.HasPrefix(s, "arn:") {
+		return ARN{}, errors.New(fmt.Sprintf(`resource "%s" should be "*" or an ARN starting with "arn:"`, s))
+	}
+	if _, err := ParsePolicyVariables(s); err != nil {
+		return ARN{}, err
+	}
+	segments := splitOutsideVariables(s, ':', 6)
+	if len(segments) != 6 {
+		return ARN{}, errors.New(fmt.Sprintf(`ARN "%s" should have the form arn:partition:service:region:account:resource`, s))
+	}
+
+	arn := ARN{
+		Partition: segments[1],
+		Service:   segments[2],
+		Region:    segments[3],
+		Account:   segments[4],
+		Resource:  segments[5],
+	}
+	if arn.Partition == "" || !isARNSegment(arn.Partition) {
+		return ARN{}, errors.New(fmt.Sprintf(`ARN "%s" has an invalid partition "%s"`, s, arn.Partition))
+	}
+	if arn.Service == "" || !isARNSegment(arn.Service) {
+		return ARN{}, errors.New(fmt.Sprintf(`ARN "%s" has an invalid service "%s"`, s, arn.Service))
+	}
+	if !isARNSegment(arn.Region) {
+		return ARN{}, errors.New(fmt.Sprintf(`ARN "%s" has an invalid region "%s"`, s, arn.Region))
+	}
+	account := replacePolicyVariables(arn.Account, "*")
+	if account != "" && account != "aws" && !isAccountId(account) && !containsWildcard(account) {
+		return ARN{}, errors.New(fmt.Sprintf(`ARN "%s" has an invalid account "%s", it should be a 12-digit account ID`, s, arn.Account))
+	}
+	if arn.Resource == "" {
+		return ARN{}, errors.New(fmt.Sprintf(`ARN "%s" has an empty resource`, s))
+	}
+
+	arn.ResourceId = arn.Resource
+	separatorIndex := indexOutsideVariables(arn.Resource, "/:")
+	if separatorIndex > 0 && !arn.isS3BucketOrObject() {
+		arn.ResourceType = arn.Resource[:separatorIndex]
+		arn.ResourceId = arn.Resource[separatorIndex+1:]
+	}
+	return arn, nil
+}
+
+/**
+ * Returns the names of the segments ("partition", "service", "region", "account", "resource") that contain a wildcard.
+ */
+func (arn ARN) WildcardSegments() []string {
+	arn = arn.withoutPolicyVariables()
+	var segments []string
+	for i, segment := range []string{arn.Partition, arn.Service, arn.Region, arn.Account, arn.Resource} {
+		if containsWildcard(segment) {
+			segments = append(segments, []string{"partition", "service", "region", "account", "resource"}[i])
+		}
+	}
+	return segments
+}
+
+/**
+ * Returns whether any segment of the ARN contains a wildcard.
+ */
+func (arn ARN) HasWildcard() bool {
+	return len(arn.WildcardSegments()) > 0
+}
+
+/**
+ * Returns whether the ARN, used as a Resource value, covers the given resource ARN.
+ *
+ * The segments are compared one by one and wildcards don't span segments, except in the resource segment
+ * which may itself contain ':' and '/'. An ARN with policy variables (other than the escapes) never matches,
+ * see MatchesInContext.
+ */
+func (arn ARN) Matches(resource string) bool {
+	return arn.MatchesInContext(resource, nil)
+}
+
+/**
+ * Like Matches, but first substitutes the policy variables of the ARN from the request context.
+ * The ARN doesn't match if a variable has neither a value in the context nor a default.
+ */
+func (arn ARN) MatchesInContext(resource string, context RequestContext) bool {
+	if arn == WildcardARN {
+		return true
+	}
+	segments := strings.SplitN(resource, ":", 6)
+	if len(segments) != 6 || segments[0] != "arn" {
+		return false
+	}
+	for i, segment := range []string{arn.Partition, arn.Service, arn.Region, arn.Account, arn.Resource} {
+		pattern, ok := substitutePolicyVariablePattern(segment, context)
+		if !ok || !patternMatch(pattern, segments[i+1], false) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the ARN with every policy variable replaced by a placeholder character, so that the escapes
+// aren't mistaken for wildcards
+func (arn ARN) withoutPolicyVariables() ARN {
+	if arn == WildcardARN {
+		return arn
+	}
+	return ARN{
+		Partition:    replacePolicyVariables(arn.Partition, "x"),
+		Service:      replacePolicyVariables(arn.Service, "x"),
+		Region:       replacePolicyVariables(arn.Region, "x"),
+		Account:      replacePolicyVariables(arn.Account, "x"),
+		Resource:     replacePolicyVariables(arn.Resource, "x"),
+		ResourceType: replacePolicyVariables(arn.ResourceType, "x"),
+		ResourceId:   replacePolicyVariables(arn.ResourceId, "x"),
+	}
+}
+
+// S3 bucket and object ARNs have no region, no account and no resource type ("arn:aws:s3:::bucket/key")
+func (arn ARN) isS3BucketOrObject() bool {
+	return arn.Service == "s3" && arn.Region == "" && arn.Account == ""
+}
+
+func isARNSegment(segment string) bool {
+	for _, c := range replacePolicyVariables(segment, "x") {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '*' || c == '?') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestARN_Parse(t *testing.T) {
+	tests := map[string]ARN{
+		"arn:aws:iam::123456789012:role/Admin": {
+			Partition: "aws", Service: "iam", Account: "123456789012", Resource: "role/Admin", ResourceType: "role", ResourceId: "Admin",
+		},
+		"arn:aws:s3:::example-bucket/path/*": {
+			Partition: "aws", Service: "s3", Resource: "example-bucket/path/*", ResourceId: "example-bucket/path/*",
+		},
+		"arn:aws:lambda:us-east-1:123456789012:function:my-function:alias": {
+			Partition: "aws", Service: "lambda", Region: "us-east-1", Account: "123456789012",
+			Resource: "function:my-function:alias", ResourceType: "function", ResourceId: "my-function:alias",
+		},
+		"arn:aws:sns:us-east-1:123456789012:my-topic": {
+			Partition: "aws", Service: "sns", Region: "us-east-1", Account: "123456789012", Resource: "my-topic", ResourceId: "my-topic",
+		},
+		"arn:aws-cn:dynamodb:*:*:table/*": {
+			Partition: "aws-cn", Service: "dynamodb", Region: "*", Account: "*", Resource: "table/*", ResourceType: "table", ResourceId: "*",
+		},
+		"arn:aws:iam::aws:policy/ReadOnlyAccess": {
+			Partition: "aws", Service: "iam", Account: "aws", Resource: "policy/ReadOnlyAccess", ResourceType: "policy", ResourceId: "ReadOnlyAccess",
+		},
+		"*": WildcardARN,
+	}
+
+	for s, expected := range tests {
+		arn, err := ParseARN(s)
+		if err != nil {
+			t.Errorf("%s: Expected error: <nil>, got: %v", s, err)
+		}
+		if !reflect.DeepEqual(arn, expected) {
+			t.Errorf("%s: Expected: %+v, got: %+v", s, expected, arn)
+		}
+		if arn.String() != s {
+			t.Errorf("Expected: %s, got: %s", s, arn.String())
+		}
+	}
+}
+
+func TestARN_ParseMalformed(t *testing.T) {
+	tests := map[string]error{
+		"lol":                              errors.New(`resource "lol" should be "*" or an ARN starting with "arn:"`),
+		"arn:aws:s3:bucket":                errors.New(`ARN "arn:aws:s3:bucket" should have the form arn:partition:service:region:account:resource`),
+		"arn::s3:::bucket":                 errors.New(`ARN "arn::s3:::bucket" has an invalid partition ""`),
+		"arn:aws::::bucket":                errors.New(`ARN "arn:aws::::bucket" has an invalid service ""`),
+		"arn:aws:S3:::bucket":              errors.New(`ARN "arn:aws:S3:::bucket" has an invalid service "S3"`),
+		"arn:aws:sqs:us east:1:queue":      errors.New(`ARN "arn:aws:sqs:us east:1:queue" has an invalid region "us east"`),
+		"arn:aws:iam::12345:role/Admin":    errors.New(`ARN "arn:aws:iam::12345:role/Admin" has an invalid account "12345", it should be a 12-digit account ID`),
+		"arn:aws:iam::123456789012:":       errors.New(`ARN "arn:aws:iam::123456789012:" has an empty resource`),
+		"arn:aws:iam::abcdefghijkl:role/x": errors.New(`ARN "arn:aws:iam::abcdefghijkl:role/x" has an invalid account "abcdefghijkl", it should be a 12-digit account ID`),
+	}
+
+	for s, expectedErr := range tests {
+		_, err := ParseARN(s)
+		if !reflect.DeepEqual(err, expectedErr) {
+			t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+		}
+	}
+}
+
+func TestARN_WildcardSegments(t *testing.T) {
+	arn, _ := ParseARN("arn:aws:dynamodb:*:123456789012:table/*")
+	expected := []string{"region", "resource"}
+
+	if !reflect.DeepEqual(arn.WildcardSegments(), expected) {
+		t.Errorf("Expected: %v, got: %v", expected, arn.WildcardSegments())
+	}
+	if !arn.HasWildcard() {
+		t.Errorf("Expected: true, got: false")
+	}
+}
+
+func TestARN_HasWildcardIfNot(t *testing.T) {
+	arn, _ := ParseARN("arn:aws:s3:::bucket/key")
+
+	if arn.HasWildcard() {
+		t.Errorf("Expected: false, got: true")
+	}
+}
+
+func TestStatement_UnmarshalParsesResources(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":"s3:GetObject","Resource":["arn:aws:s3:::bucket/*","*"]}`)
+	var stat Statement
+	err := stat.UnmarshalJSON(data)
+
+	expected := []ARN{
+		{Partition: "aws", Service: "s3", Resource: "bucket/*", ResourceId: "bucket/*"},
+		WildcardARN,
+	}
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if !reflect.DeepEqual(stat.Resources, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, stat.Resources)
+	}
+}
+
+func TestStatement_UnmarshalMalformedResource(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":"s3:GetObject","Resource":["arn:aws:s3:::bucket/*","arn:aws:s3:bucket"]}`)
+	var stat Statement
+	expectedErr := &ParseError{Code: CodeInvalidResource, Path: "/Resource/1", Message: `ARN "arn:aws:s3:bucket" should have the form arn:partition:service:region:account:resource`, Err: errors.New(`ARN "arn:aws:s3:bucket" should have the form arn:partition:service:region:account:resource`), Line: 1, Column: 79}
+
+	err := stat.UnmarshalJSON(data)
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+	}
+}
+
+func TestStatement_ResourceAccountsRegionsAndServices(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":"*","Resource":[
+		"arn:aws:s3:::bucket/*",
+		"arn:aws:dynamodb:us-east-1:123456789012:table/Orders",
+		"arn:aws:dynamodb:eu-west-1:123456789012:table/Orders",
+		"arn:aws:sqs:*:210987654321:queue",
+		"*"
+	]}`)
+	var stat Statement
+	if err := stat.UnmarshalJSON(data); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+
+	if expected := []string{"123456789012", "210987654321"}; !reflect.DeepEqual(stat.ResourceAccounts(), expected) {
+		t.Errorf("Expected: %v, got: %v", expected, stat.ResourceAccounts())
+	}
+	if expected := []string{"us-east-1", "eu-west-1"}; !reflect.DeepEqual(stat.ResourceRegions(), expected) {
+		t.Errorf("Expected: %v, got: %v", expected, stat.ResourceRegions())
+	}
+	if expected := []string{"s3", "dynamodb", "sqs"}; !reflect.DeepEqual(stat.ResourceServices(), expected) {
+		t.Errorf("Expected: %v, got: %v", expected, stat.ResourceServices())
+	}
+}
+
+func TestARN_Matches(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		resource string
+		expected bool
+	}{
+		{"*", "arn:aws:s3:::bucket/key", true},
+		{"arn:aws:s3:::bucket/*", "arn:aws:s3:::bucket/path/key", true},
+		{"arn:aws:s3:::bucket/*", "arn:aws:s3:::other/key", false},
+		{"arn:aws:s3:::bucket", "arn:aws:s3:::bucket/key", false},
+		{"arn:aws:dynamodb:*:123456789012:table/*", "arn:aws:dynamodb:us-east-1:123456789012:table/Orders", true},
+		{"arn:aws:dynamodb:*:123456789012:table/*", "arn:aws:dynamodb:us-east-1:210987654321:table/Orders", false},
+		{"arn:aws:lambda:us-east-1:123456789012:function:*", "arn:aws:lambda:us-east-1:123456789012:function:f:alias", true},
+		{"arn:aws:iam::123456789012:role/app-?", "arn:aws:iam::123456789012:role/app-1", true},
+		{"arn:aws:s3:::bucket/*", "not an arn", false},
+	}
+
+	for _, test := range tests {
+		arn, err := ParseARN(test.pattern)
+		if err != nil {
+			t.Fatalf("Expected error: <nil>, got: %v", err)
+		}
+		if arn.Matches(test.resource) != test.expected {
+			t.Errorf("%s matches %s: Expected: %v, got: %v", test.pattern, test.resource, test.expected, !test.expected)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/**
+ * Condition represents the Condition block of a statement as operator -> condition key -> values.
+ *
+ * The operators are kept as written (e.g. "ForAnyValue:StringLikeIfExists"), see ParseConditionOperator for their parts.
+ * Numeric and boolean values are kept in their string form (e.g. 10 becomes "10" and true becomes "true").
+ *
+ * for grammar see https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_elements_condition_operators.html
+ */
+type Condition map[string]map[string][]string
+
+/**
+ * ConditionOperator struct represents a parsed condition operator.
+ *
+ * Name is the operator as written, Base is the operator without the set qualifier and the IfExists suffix
+ * (e.g. "StringLike"), IfExists is true if the operator had the IfExists suffix and SetQualifier is
+ * "ForAllValues", "ForAnyValue" or "" if the operator had no set qualifier.
+ */
+type ConditionOperator struct {
+	Name         string
+	Base         string
+	IfExists     bool
+	SetQualifier string
+}
+
+// The documented base operators, grouped by family
+var conditionOperatorFamilies = map[string][]string{
+	"String": {
+		"StringEquals", "StringNotEquals", "StringEqualsIgnoreCase", "StringNotEqualsIgnoreCase", "StringLike", "StringNotLike",
+	},
+	"Numeric": {
+		"NumericEquals", "NumericNotEquals", "NumericLessThan", "NumericLessThanEquals", "NumericGreaterThan", "NumericGreaterThanEquals",
+	},
+	"Date": {
+		"DateEquals", "DateNotEquals", "DateLessThan", "DateLessThanEquals", "DateGreaterThan", "DateGreaterThanEquals",
+	},
+	"Bool":         {"Bool"},
+	"BinaryEquals": {"BinaryEquals"},
+	"IpAddress":    {"IpAddress", "NotIpAddress"},
+	"Arn":          {"ArnEquals", "ArnLike", "ArnNotEquals", "ArnNotLike"},
+	"Null":         {"Null"},
+}
+
+/**
+ * Parses a condition operator such as "StringEquals", "NumericLessThanIfExists" or "ForAllValues:StringLike".
+ *
+ * Returns an error if the base operator is not one of the documented operators, if the set qualifier is not
+ * "ForAllValues" or "ForAnyValue", or if the IfExists suffix is used with the Null operator.
+ */
+func ParseConditionOperator(name string) (ConditionOperator, error) {
+	operator := ConditionOperator{Name: name, Base: name}
+
+	if qualifier, base, found := strings.Cut(name, ":"); found {
+		if qualifier != "ForAllValues" && qualifier != "ForAnyValue" {
+			return ConditionOperator{}, errors.New(fmt.Sprintf(`condition operator "%s" has an unknown set qualifier "%s", it should be "ForAllValues" or "ForAnyValue"`, name, qualifier))
+		}
+		operator.SetQualifier = qualifier
+		operator.Base = base
+	}
+	if base, found := strings.CutSuffix(operator.Base, "IfExists"); found {
+		operator.IfExists = true
+		operator.Base = base
+	}
+
+	if operator.Family() == "" {
+		return ConditionOperator{}, errors.New(fmt.Sprintf(`unknown condition operator "%s"`, name))
+	}
+	if operator.IfExists && operator.Base == "Null" {
+		return ConditionOperator{}, errors.New(`condition operator "Null" can't have the IfExists suffix`)
+	}
+	return operator, nil
+}
+
+/**
+ * Returns the family of the operator ("String", "Numeric", "Date", "Bool", "BinaryEquals", "IpAddress", "Arn" or "Null"),
+ * or "" if the base operator is unknown.
+ */
+func (operator ConditionOperator) Family() string {
+	for family, operators := range conditionOperatorFamilies {
+		if containsString(operators, operator.Base) {
+			return family
+		}
+	}
+	return ""
+}
+
+/**
+ * Returns the parsed operators of the condition, sorted by name.
+ * Operators that don't parse are skipped, which can't happen for a Condition built by Statement.UnmarshalJSON.
+ */
+func (condition Condition) Operators() []ConditionOperator {
+	var operators []ConditionOperator
+	for _, name := range sortedKeys(condition) {
+		if operator, err := ParseConditionOperator(name); err == nil {
+			operators = append(operators, operator)
+		}
+	}
+	return operators
+}
+
+// Whether any operator of the condition tests the condition key, which like in IAM is case-insensitive
+func (condition Condition) hasKey(key string) bool {
+	for _, keys := range condition {
+		for conditionKey := range keys {
+			if strings.EqualFold(conditionKey, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Builds the Condition from the raw value of the "Condition" key, returning every error found
+func newCondition(conditionValue interface{}) (Condition, []error) {
+	conditionMap, ok := conditionValue.(map[string]interface{})
+	if !ok {
+		return nil, []error{newParseError(CodeInvalidType, "", "condition value should be a map")}
+	}
+
+	var errs []error
+	condition := Condition{}
+	for _, operatorName := range sortedKeys(conditionMap) {
+		operatorPath := jsonPointer("", operatorName)
+		if _, err := ParseConditionOperator(operatorName); err != nil {
+			errs = append(errs, wrapParseError(CodeInvalidCondition, operatorPath, err))
+			continue
+		}
+		keyMap, ok := conditionMap[operatorName].(map[string]interface{})
+		if !ok {
+			errs = append(errs, newParseError(CodeInvalidType, operatorPath, fmt.Sprintf(`value of condition operator "%s" should be a map of condition keys`, operatorName)))
+			continue
+		}
+
+		condition[operatorName] = map[string][]string{}
+		for _, key := range sortedKeys(keyMap) {
+			keyPath := jsonPointer(operatorPath, key)
+			if key == "" {
+				errs = append(errs, newParseError(CodeInvalidCondition, keyPath, fmt.Sprintf(`condition operator "%s" has an empty condition key`, operatorName)))
+				continue
+			}
+			values, err := conditionValues(keyMap[key])
+			if err != nil {
+				errs = append(errs, newParseError(CodeInvalidType, keyPath, fmt.Sprintf(`condition key "%s" of condition operator "%s": %s`, key, operatorName, err.Error())))
+				continue
+			}
+			valid := true
+			for i, value := range values {
+				if _, err := ParsePolicyVariables(value); err != nil {
+					errs = append(errs, &ParseError{
+						Code:    CodeInvalidPolicyVariable,
+						Path:    operatorPath + elementPath(key, keyMap[key], i),
+						Message: fmt.Sprintf(`condition key "%s" of condition operator "%s": %s`, key, operatorName, err.Error()),
+						Err:     err,
+					})
+					valid = false
+				}
+			}
+			if valid {
+				condition[operatorName][key] = values
+			}
+		}
+	}
+	return condition, errs
+}
+
+func conditionValues(value interface{}) ([]string, error) {
+	if array, ok := value.([]interface{}); ok {
+		var values []string
+		for _, element := range array {
+			elementString, ok := conditionValueString(element)
+			if !ok {
+				return nil, errors.New("condition value should be a string, a number, a boolean or an array of them")
+			}
+			values = append(values, elementString)
+		}
+		return values, nil
+	}
+	if valueString, ok := conditionValueString(value); ok {
+		return []string{valueString}, nil
+	}
+	return nil, errors.New("condition value should be a string, a number, a boolean or an array of them")
+}
+
+func conditionValueString(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(value), true
+	}
+	return "", false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,156 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConditionOperator_Parse(t *testing.T) {
+	tests := map[string]ConditionOperator{
+		"StringEquals":                     {Name: "StringEquals", Base: "StringEquals"},
+		"NumericLessThanIfExists":          {Name: "NumericLessThanIfExists", Base: "NumericLessThan", IfExists: true},
+		"ForAllValues:StringLike":          {Name: "ForAllValues:StringLike", Base: "StringLike", SetQualifier: "ForAllValues"},
+		"ForAnyValue:StringEqualsIfExists": {Name: "ForAnyValue:StringEqualsIfExists", Base: "StringEquals", IfExists: true, SetQualifier: "ForAnyValue"},
+		"Null":                             {Name: "Null", Base: "Null"},
+		"NotIpAddress":                     {Name: "NotIpAddress", Base: "NotIpAddress"},
+		"BinaryEquals":                     {Name: "BinaryEquals", Base: "BinaryEquals"},
+		"ArnNotLikeIfExists":               {Name: "ArnNotLikeIfExists", Base: "ArnNotLike", IfExists: true},
+		"DateGreaterThanEquals":            {Name: "DateGreaterThanEquals", Base: "DateGreaterThanEquals"},
+		"Bool":                             {Name: "Bool", Base: "Bool"},
+	}
+
+	for name, expected := range tests {
+		operator, err := ParseConditionOperator(name)
+		if err != nil {
+			t.Errorf("%s: Expected error: <nil>, got: %v", name, err)
+		}
+		if !reflect.DeepEqual(operator, expected) {
+			t.Errorf("%s: Expected: %+v, got: %+v", name, expected, operator)
+		}
+	}
+}
+
+func TestConditionOperator_ParseInvalid(t *testing.T) {
+	tests := map[string]error{
+		"StringEqual":              errors.New(`unknown condition operator "StringEqual"`),
+		"IfExists":                 errors.New(`unknown condition operator "IfExists"`),
+		"ForSomeValues:StringLike": errors.New(`condition operator "ForSomeValues:StringLike" has an unknown set qualifier "ForSomeValues", it should be "ForAllValues" or "ForAnyValue"`),
+		"NullIfExists":             errors.New(`condition operator "Null" can't have the IfExists suffix`),
+		"stringequals":             errors.New(`unknown condition operator "stringequals"`),
+	}
+
+	for name, expectedErr := range tests {
+		_, err := ParseConditionOperator(name)
+		if !reflect.DeepEqual(err, expectedErr) {
+			t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+		}
+	}
+}
+
+func TestConditionOperator_Family(t *testing.T) {
+	tests := map[string]string{
+		"StringNotEqualsIgnoreCase": "String",
+		"NumericEquals":             "Numeric",
+		"DateLessThan":              "Date",
+		"Bool":                      "Bool",
+		"BinaryEquals":              "BinaryEquals",
+		"NotIpAddress":              "IpAddress",
+		"ArnLike":                   "Arn",
+		"Null":                      "Null",
+	}
+
+	for name, expected := range tests {
+		operator, _ := ParseConditionOperator(name)
+		if operator.Family() != expected {
+			t.Errorf("%s: Expected: %s, got: %s", name, expected, operator.Family())
+		}
+	}
+}
+
+func TestStatement_UnmarshalParsesConditions(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":"s3:GetObject","Resource":"*","Condition":{
+		"StringLike":{"s3:prefix":["home/","home/*"]},
+		"NumericLessThanIfExists":{"s3:max-keys":10},
+		"Bool":{"aws:SecureTransport":true}
+	}}`)
+	var stat Statement
+	err := stat.UnmarshalJSON(data)
+
+	expected := Condition{
+		"StringLike":              {"s3:prefix": {"home/", "home/*"}},
+		"NumericLessThanIfExists": {"s3:max-keys": {"10"}},
+		"Bool":                    {"aws:SecureTransport": {"true"}},
+	}
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if !reflect.DeepEqual(stat.Conditions, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, stat.Conditions)
+	}
+}
+
+func TestStatement_UnmarshalUnknownConditionOperator(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":"s3:GetObject","Resource":"*","Condition":{"StringEqual":{"s3:prefix":"home/"}}}`)
+	var stat Statement
+	expectedErr := &ParseError{Code: CodeInvalidCondition, Path: "/Condition/StringEqual", Message: `unknown condition operator "StringEqual"`, Err: errors.New(`unknown condition operator "StringEqual"`), Line: 1, Column: 71}
+
+	err := stat.UnmarshalJSON(data)
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+	}
+}
+
+func TestStatement_UnmarshalConditionIsNotAMap(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":"s3:GetObject","Resource":"*","Condition":["StringEquals"]}`)
+	var stat Statement
+	expectedErr := &ParseError{Code: CodeInvalidType, Path: "/Condition", Message: "condition value should be a map", Line: 1, Column: 58}
+
+	err := stat.UnmarshalJSON(data)
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+	}
+}
+
+func TestStatement_UnmarshalConditionOperatorValueIsNotAMap(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":"s3:GetObject","Resource":"*","Condition":{"StringEquals":"s3:prefix"}}`)
+	var stat Statement
+	expectedErr := &ParseError{Code: CodeInvalidType, Path: "/Condition/StringEquals", Message: `value of condition operator "StringEquals" should be a map of condition keys`, Line: 1, Column: 71}
+
+	err := stat.UnmarshalJSON(data)
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+	}
+}
+
+func TestStatement_UnmarshalConditionValueOfInvalidType(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":"s3:GetObject","Resource":"*","Condition":{"StringEquals":{"s3:prefix":{"nested":"map"}}}}`)
+	var stat Statement
+	expectedErr := &ParseError{Code: CodeInvalidType, Path: "/Condition/StringEquals/s3:prefix", Message: `condition key "s3:prefix" of condition operator "StringEquals": condition value should be a string, a number, a boolean or an array of them`, Line: 1, Column: 87}
+
+	err := stat.UnmarshalJSON(data)
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+	}
+}
+
+func TestCondition_Operators(t *testing.T) {
+	condition := Condition{
+		"StringLike":               {"s3:prefix": {"home/"}},
+		"ForAnyValue:StringEquals": {"aws:TagKeys": {"env"}},
+		"Bool":                     {"aws:SecureTransport": {"true"}},
+	}
+	expected := []ConditionOperator{
+		{Name: "Bool", Base: "Bool"},
+		{Name: "ForAnyValue:StringEquals", Base: "StringEquals", SetQualifier: "ForAnyValue"},
+		{Name: "StringLike", Base: "StringLike"},
+	}
+
+	if !reflect.DeepEqual(condition.Operators(), expected) {
+		t.Errorf("Expected: %v, got: %v", expected, condition.Operators())
+	}
+}
@@ -0,0 +1,273 @@
+package iamrolepolicyparsing
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/**
+ * ConditionOutcome is the outcome of evaluating a single condition key of a Condition block against a request context.
+ *
+ * ConditionUnknownKey means that the key was absent from the request context and the operator needed it
+ * (i.e. it was not an IfExists, Null, ForAllValues or a negated operator without a set qualifier), so the condition is not met.
+ */
+type ConditionOutcome int
+
+const (
+	ConditionMatched ConditionOutcome = iota
+	ConditionNotMatched
+	ConditionUnknownKey
+)
+
+func (outcome ConditionOutcome) String() string {
+	switch outcome {
+	case ConditionMatched:
+		return "matched"
+	case ConditionNotMatched:
+		return "not-matched"
+	case ConditionUnknownKey:
+		return "unknown-key"
+	}
+	return fmt.Sprintf("ConditionOutcome(%d)", int(outcome))
+}
+
+/**
+ * ConditionResult struct holds the outcome of evaluating the condition key Key under the operator Operator.
+ */
+type ConditionResult struct {
+	Operator string
+	Key      string
+	Outcome  ConditionOutcome
+}
+
+// The negated operators and the operators they negate
+var negatedConditionOperators = map[string]string{
+	"StringNotEquals":           "StringEquals",
+	"StringNotEqualsIgnoreCase": "StringEqualsIgnoreCase",
+	"StringNotLike":             "StringLike",
+	"NumericNotEquals":          "NumericEquals",
+	"DateNotEquals":             "DateEquals",
+	"NotIpAddress":              "IpAddress",
+	"ArnNotEquals":              "ArnEquals",
+	"ArnNotLike":                "ArnLike",
+}
+
+/**
+ * Evaluates every condition key of the Condition block against the request context,
+ * sorted by operator and then by key.
+ *
+ * Within a key, the request value has to match one of the policy values (or none of them for negated operators).
+ * With ForAnyValue at least one of the request values has to match, with ForAllValues every one of them.
+ * Condition keys are looked up case-insensitively. Policy variables in the values of the String and Arn operators
+ * are substituted from the request context; a value whose variable can't be substituted matches nothing.
+ *
+ * for semantics see https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_elements_condition_operators.html
+ */
+func (condition Condition) Evaluate(context RequestContext) []ConditionResult {
+	var results []ConditionResult
+	for _, operator := range condition.Operators() {
+		keys := condition[operator.Name]
+		for _, key := range sortedKeys(keys) {
+			results = append(results, ConditionResult{
+				Operator: operator.Name,
+				Key:      key,
+				Outcome:  operator.evaluate(keys[key], context.lookup(key), context),
+			})
+		}
+	}
+	return results
+}
+
+/**
+ * Returns whether every condition key of the Condition block is matched by the request context.
+ * An empty or nil Condition is always satisfied.
+ */
+func (condition Condition) IsSatisfied(context RequestContext) bool {
+	for _, result := range condition.Evaluate(context) {
+		if result.Outcome != ConditionMatched {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the values of the key, ignoring case in the key name, or nil if the key is absent
+func (context RequestContext) lookup(key string) []string {
+	if values, ok := context[key]; ok {
+		return values
+	}
+	for contextKey, values := range context {
+		if strings.EqualFold(contextKey, key) {
+			return values
+		}
+	}
+	return nil
+}
+
+func (operator ConditionOperator) evaluate(policyValues []string, contextValues []string, context RequestContext) ConditionOutcome {
+	if operator.Base == "Null" {
+		return outcomeOf(evaluateNull(policyValues, contextValues != nil))
+	}
+
+	base, negated := operator.Base, false
+	if positive, ok := negatedConditionOperators[operator.Base]; ok {
+		base, negated = positive, true
+	}
+
+	if len(contextValues) == 0 {
+		if operator.IfExists || operator.SetQualifier == "ForAllValues" || negated && operator.SetQualifier == "" {
+			return ConditionMatched
+		}
+		return ConditionUnknownKey
+	}
+
+	// whether a single request value satisfies the operator
+	satisfies := func(contextValue string) bool {
+		for _, policyValue := range policyValues {
+			if compareConditionValues(base, policyValue, contextValue, context) {
+				return !negated
+			}
+		}
+		return negated
+	}
+
+	matchesAny, matchesAll := false, true
+	for _, contextValue := range contextValues {
+		if satisfies(contextValue) {
+			matchesAny = true
+		} else {
+			matchesAll = false
+		}
+	}
+	if operator.SetQualifier == "ForAnyValue" || (operator.SetQualifier == "" && !negated) {
+		return outcomeOf(matchesAny)
+	}
+	return outcomeOf(matchesAll)
+}
+
+// Null with "true" requires the key to be absent, with "false" it requires the key to be present
+func evaluateNull(policyValues []string, present bool) bool {
+	for _, policyValue := range policyValues {
+		if strings.EqualFold(policyValue, "true") && !present || strings.EqualFold(policyValue, "false") && present {
+			return true
+		}
+	}
+	return false
+}
+
+func outcomeOf(matched bool) ConditionOutcome {
+	if matched {
+		return ConditionMatched
+	}
+	return ConditionNotMatched
+}
+
+// Compares a request value to a policy value using a non-negated base operator
+func compareConditionValues(base string, policyValue string, contextValue string, context RequestContext) bool {
+	switch base {
+	case "StringEquals":
+		substituted, ok := SubstitutePolicyVariables(policyValue, context)
+		return ok && substituted == contextValue
+	case "StringEqualsIgnoreCase":
+		substituted, ok := SubstitutePolicyVariables(policyValue, context)
+		return ok && strings.EqualFold(substituted, contextValue)
+	case "StringLike":
+		pattern, ok := substitutePolicyVariablePattern(policyValue, context)
+		return ok && patternMatch(pattern, contextValue, false)
+	case "NumericEquals", "NumericLessThan", "NumericLessThanEquals", "NumericGreaterThan", "NumericGreaterThanEquals":
+		policyNumber, policyErr := strconv.ParseFloat(policyValue, 64)
+		contextNumber, contextErr := strconv.ParseFloat(contextValue, 64)
+		if policyErr != nil || contextErr != nil {
+			return false
+		}
+		return compareOrdered(strings.TrimPrefix(base, "Numeric"), contextNumber, policyNumber)
+	case "DateEquals", "DateLessThan", "DateLessThanEquals", "DateGreaterThan", "DateGreaterThanEquals":
+		policyDate, policyOk := parseConditionDate(policyValue)
+		contextDate, contextOk := parseConditionDate(contextValue)
+		if !policyOk || !contextOk {
+			return false
+		}
+		return compareOrdered(strings.TrimPrefix(base, "Date"), float64(contextDate.UnixNano()), float64(policyDate.UnixNano()))
+	case "Bool":
+		return strings.EqualFold(policyValue, contextValue)
+	case "BinaryEquals":
+		policyBytes, policyErr := base64.StdEncoding.DecodeString(policyValue)
+		contextBytes, contextErr := base64.StdEncoding.DecodeString(contextValue)
+		if policyErr != nil || contextErr != nil {
+			return policyValue == contextValue
+		}
+		return bytes.Equal(policyBytes, contextBytes)
+	case "IpAddress":
+		return ipAddressMatches(policyValue, contextValue)
+	case "ArnEquals", "ArnLike":
+		return arnLike(policyValue, contextValue, context)
+	}
+	return false
+}
+
+func compareOrdered(comparison string, contextValue float64, policyValue float64) bool {
+	switch comparison {
+	case "Equals":
+		return contextValue == policyValue
+	case "LessThan":
+		return contextValue < policyValue
+	case "LessThanEquals":
+		return contextValue <= policyValue
+	case "GreaterThan":
+		return contextValue > policyValue
+	case "GreaterThanEquals":
+		return contextValue >= policyValue
+	}
+	return false
+}
+
+// Dates are ISO 8601 (e.g. "2024-01-01T00:00:00Z" or "2024-01-01") or seconds since the epoch
+func parseConditionDate(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05Z0700", "2006-01-02T15:04Z07:00", "2006-01-02"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), true
+	}
+	return time.Time{}, false
+}
+
+// The policy value is an IP address or a CIDR block, the request value an IP address
+func ipAddressMatches(policyValue string, contextValue string) bool {
+	address, err := netip.ParseAddr(contextValue)
+	if err != nil {
+		return false
+	}
+	if !strings.Contains(policyValue, "/") {
+		policyAddress, err := netip.ParseAddr(policyValue)
+		return err == nil && policyAddress.Unmap() == address.Unmap()
+	}
+	prefix, err := netip.ParsePrefix(policyValue)
+	return err == nil && prefix.Contains(address.Unmap())
+}
+
+// ARNs are compared segment by segment, wildcards don't span segments except in the resource segment
+func arnLike(pattern string, value string, context RequestContext) bool {
+	if pattern == "*" {
+		return true
+	}
+	patternSegments := splitOutsideVariables(pattern, ':', 6)
+	valueSegments := strings.SplitN(value, ":", 6)
+	if len(patternSegments) != 6 || len(valueSegments) != 6 {
+		return false
+	}
+	for i := range patternSegments {
+		segmentPattern, ok := substitutePolicyVariablePattern(patternSegments[i], context)
+		if !ok || !patternMatch(segmentPattern, valueSegments[i], false) {
+			return false
+		}
+	}
+	return true
+}
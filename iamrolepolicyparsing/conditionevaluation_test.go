@@ -0,0 +1,173 @@
+package iamrolepolicyparsing
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCondition_EvaluateOperators(t *testing.T) {
+	tests := []struct {
+		operator string
+		values   []string
+		context  []string
+		expected ConditionOutcome
+	}{
+		{"StringEquals", []string{"a", "b"}, []string{"b"}, ConditionMatched},
+		{"StringEquals", []string{"a", "b"}, []string{"B"}, ConditionNotMatched},
+		{"StringEqualsIgnoreCase", []string{"a"}, []string{"A"}, ConditionMatched},
+		{"StringNotEquals", []string{"a", "b"}, []string{"c"}, ConditionMatched},
+		{"StringNotEquals", []string{"a", "b"}, []string{"a"}, ConditionNotMatched},
+		{"StringNotEqualsIgnoreCase", []string{"a"}, []string{"A"}, ConditionNotMatched},
+		{"StringLike", []string{"home/*"}, []string{"home/user/file"}, ConditionMatched},
+		{"StringNotLike", []string{"home/*"}, []string{"other/file"}, ConditionMatched},
+		{"NumericLessThan", []string{"10"}, []string{"9"}, ConditionMatched},
+		{"NumericLessThan", []string{"10"}, []string{"10"}, ConditionNotMatched},
+		{"NumericLessThanEquals", []string{"10"}, []string{"10"}, ConditionMatched},
+		{"NumericGreaterThan", []string{"10"}, []string{"10.5"}, ConditionMatched},
+		{"NumericGreaterThanEquals", []string{"10"}, []string{"9"}, ConditionNotMatched},
+		{"NumericEquals", []string{"10"}, []string{"ten"}, ConditionNotMatched},
+		{"NumericNotEquals", []string{"10"}, []string{"11"}, ConditionMatched},
+		{"DateLessThan", []string{"2024-01-01T00:00:00Z"}, []string{"2023-12-31T23:59:59Z"}, ConditionMatched},
+		{"DateGreaterThan", []string{"2024-01-01"}, []string{"2024-06-01T12:00:00Z"}, ConditionMatched},
+		{"DateEquals", []string{"1704067200"}, []string{"2024-01-01T00:00:00Z"}, ConditionMatched},
+		{"DateNotEquals", []string{"2024-01-01T00:00:00Z"}, []string{"2024-01-01T00:00:00Z"}, ConditionNotMatched},
+		{"Bool", []string{"true"}, []string{"true"}, ConditionMatched},
+		{"Bool", []string{"true"}, []string{"false"}, ConditionNotMatched},
+		{"BinaryEquals", []string{"QmluYXJ5VmFsdWU="}, []string{"QmluYXJ5VmFsdWU="}, ConditionMatched},
+		{"BinaryEquals", []string{"QmluYXJ5VmFsdWU="}, []string{"T3RoZXI="}, ConditionNotMatched},
+		{"IpAddress", []string{"203.0.113.0/24"}, []string{"203.0.113.7"}, ConditionMatched},
+		{"IpAddress", []string{"203.0.113.0/24"}, []string{"198.51.100.7"}, ConditionNotMatched},
+		{"IpAddress", []string{"2001:db8::/32"}, []string{"2001:db8::1"}, ConditionMatched},
+		{"IpAddress", []string{"203.0.113.7"}, []string{"203.0.113.7"}, ConditionMatched},
+		{"NotIpAddress", []string{"203.0.113.0/24"}, []string{"198.51.100.7"}, ConditionMatched},
+		{"ArnLike", []string{"arn:aws:iam::123456789012:role/app-*"}, []string{"arn:aws:iam::123456789012:role/app-web"}, ConditionMatched},
+		{"ArnEquals", []string{"arn:aws:sns:*:123456789012:topic"}, []string{"arn:aws:sns:us-east-1:123456789012:topic"}, ConditionMatched},
+		{"ArnNotLike", []string{"arn:aws:iam::123456789012:role/app-*"}, []string{"arn:aws:iam::123456789012:role/app-web"}, ConditionNotMatched},
+		{"StringEqualsIfExists", []string{"a"}, []string{"b"}, ConditionNotMatched},
+	}
+
+	for _, test := range tests {
+		condition := Condition{test.operator: {"key": test.values}}
+		results := condition.Evaluate(RequestContext{"key": test.context})
+		expected := []ConditionResult{{Operator: test.operator, Key: "key", Outcome: test.expected}}
+		if !reflect.DeepEqual(results, expected) {
+			t.Errorf("%s %v on %v: Expected: %v, got: %v", test.operator, test.values, test.context, expected, results)
+		}
+	}
+}
+
+func TestCondition_EvaluateMissingKey(t *testing.T) {
+	tests := map[string]ConditionOutcome{
+		"StringEquals":                   ConditionUnknownKey,
+		"StringEqualsIfExists":           ConditionMatched,
+		"StringNotEquals":                ConditionMatched,
+		"ForAllValues:StringEquals":      ConditionMatched,
+		"ForAnyValue:StringEquals":       ConditionUnknownKey,
+		"ForAnyValue:StringNotEquals":    ConditionUnknownKey,
+		"NumericLessThanIfExists":        ConditionMatched,
+		"IpAddress":                      ConditionUnknownKey,
+		"ForAnyValue:StringLikeIfExists": ConditionMatched,
+	}
+
+	for operator, expected := range tests {
+		condition := Condition{operator: {"key": {"value"}}}
+		results := condition.Evaluate(RequestContext{"other": {"value"}})
+		if len(results) != 1 || results[0].Outcome != expected {
+			t.Errorf("%s: Expected: %v, got: %v", operator, expected, results)
+		}
+	}
+}
+
+func TestCondition_EvaluateNull(t *testing.T) {
+	condition := Condition{"Null": {"aws:TokenIssueTime": {"true"}, "aws:MultiFactorAuthAge": {"false"}}}
+	expected := []ConditionResult{
+		{Operator: "Null", Key: "aws:MultiFactorAuthAge", Outcome: ConditionMatched},
+		{Operator: "Null", Key: "aws:TokenIssueTime", Outcome: ConditionNotMatched},
+	}
+
+	results := condition.Evaluate(RequestContext{"aws:TokenIssueTime": {"2024-01-01T00:00:00Z"}, "aws:MultiFactorAuthAge": {"30"}})
+
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, results)
+	}
+}
+
+func TestCondition_EvaluateSetQualifiers(t *testing.T) {
+	tests := []struct {
+		operator string
+		context  []string
+		expected ConditionOutcome
+	}{
+		{"ForAllValues:StringEquals", []string{"env", "team"}, ConditionMatched},
+		{"ForAllValues:StringEquals", []string{"env", "owner"}, ConditionNotMatched},
+		{"ForAnyValue:StringEquals", []string{"owner", "team"}, ConditionMatched},
+		{"ForAnyValue:StringEquals", []string{"owner", "cost"}, ConditionNotMatched},
+		{"ForAllValues:StringNotEquals", []string{"owner", "cost"}, ConditionMatched},
+		{"ForAllValues:StringNotEquals", []string{"owner", "env"}, ConditionNotMatched},
+		{"ForAnyValue:StringNotEquals", []string{"env", "owner"}, ConditionMatched},
+		{"ForAllValues:StringEquals", []string{}, ConditionMatched},
+	}
+
+	for _, test := range tests {
+		condition := Condition{test.operator: {"aws:TagKeys": {"env", "team"}}}
+		results := condition.Evaluate(RequestContext{"aws:TagKeys": test.context})
+		if len(results) != 1 || results[0].Outcome != test.expected {
+			t.Errorf("%s on %v: Expected: %v, got: %v", test.operator, test.context, test.expected, results)
+		}
+	}
+}
+
+func TestCondition_EvaluateLooksUpKeysCaseInsensitively(t *testing.T) {
+	condition := Condition{"StringEquals": {"aws:PrincipalTag/Team": {"blue"}}}
+
+	if !condition.IsSatisfied(RequestContext{"aws:principaltag/team": {"blue"}}) {
+		t.Errorf("Expected: true, got: false")
+	}
+}
+
+func TestCondition_IsSatisfied(t *testing.T) {
+	condition := Condition{
+		"StringLike": {"s3:prefix": {"home/*"}},
+		"Bool":       {"aws:SecureTransport": {"true"}},
+	}
+
+	if !condition.IsSatisfied(RequestContext{"s3:prefix": {"home/me"}, "aws:SecureTransport": {"true"}}) {
+		t.Errorf("Expected: true, got: false")
+	}
+	if condition.IsSatisfied(RequestContext{"s3:prefix": {"home/me"}}) {
+		t.Errorf("Expected: false, got: true")
+	}
+	if !Condition(nil).IsSatisfied(nil) {
+		t.Errorf("Expected: true, got: false")
+	}
+}
+
+func TestStatement_EvaluateConditions(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":"s3:ListBucket","Resource":"*","Condition":{
+		"StringLike":{"s3:prefix":"home/*"},
+		"IpAddress":{"aws:SourceIp":["10.0.0.0/8"]}
+	}}`)
+	var stat Statement
+	if err := stat.UnmarshalJSON(data); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+	expected := []ConditionResult{
+		{Operator: "IpAddress", Key: "aws:SourceIp", Outcome: ConditionUnknownKey},
+		{Operator: "StringLike", Key: "s3:prefix", Outcome: ConditionMatched},
+	}
+
+	results := stat.EvaluateConditions(RequestContext{"s3:prefix": {"home/me"}})
+
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, results)
+	}
+	if stat.MatchesConditions(RequestContext{"s3:prefix": {"home/me"}}) {
+		t.Errorf("Expected: false, got: true")
+	}
+}
+
+func TestConditionOutcome_String(t *testing.T) {
+	if ConditionUnknownKey.String() != "unknown-key" {
+		t.Errorf("Expected: unknown-key, got: %s", ConditionUnknownKey.String())
+	}
+}
@@ -0,0 +1,75 @@
+package iamrolepolicyparsing
+
+/**
+ * EffectivePolicy struct holds the policies that together decide what a role can do:
+ *
+ *   IdentityPolicies       - the policy documents of the role, inline and managed, any of which can grant access
+ *   PermissionsBoundary    - the permissions boundary of the role, nil if it has none
+ *   ServiceControlPolicies - the SCPs on the path from the organization root to the account, one document per level
+ *                            (the root, each OU, the account), every level having to allow a request. Several SCPs
+ *                            attached to the same level are alternatives, they have to be merged into one document.
+ *                            Empty if the account is not in an organization, or for the management account
+ *                            which SCPs don't apply to.
+ *
+ * for the evaluation logic see https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_evaluation-logic.html
+ */
+type EffectivePolicy struct {
+	IdentityPolicies       []*PolicyDocument
+	PermissionsBoundary    *PolicyDocument
+	ServiceControlPolicies []*PolicyDocument
+}
+
+/**
+ * EffectiveEvaluationResult struct holds the Decision for a request and the result of every policy that took part in it,
+ * IdentityPolicies and ServiceControlPolicies in the order of the EffectivePolicy.
+ * PermissionsBoundary is nil if the EffectivePolicy had none.
+ */
+type EffectiveEvaluationResult struct {
+	Decision               Decision
+	IdentityPolicies       []EvaluationResult
+	PermissionsBoundary    *EvaluationResult
+	ServiceControlPolicies []EvaluationResult
+}
+
+/**
+ * Evaluates a request of the role: an explicit deny in any of the policies denies the request, and otherwise
+ * the request is allowed only if one of the identity policies allows it and the permissions boundary and every level
+ * of SCPs allow it too. The boundary and the SCPs never grant access by themselves.
+ *
+ * action, resource and context are as for (pd *PolicyDocument)IsAllowed in evaluation.go.
+ */
+func (policy EffectivePolicy) IsAllowed(action string, resource string, context RequestContext) EffectiveEvaluationResult {
+	var result EffectiveEvaluationResult
+	// the identity policies are a union, the other policies an intersection
+	identityDecision := DecisionImplicitDeny
+	for _, identityPolicy := range policy.IdentityPolicies {
+		identityResult := identityPolicy.IsAllowed("", action, resource, context)
+		result.IdentityPolicies = append(result.IdentityPolicies, identityResult)
+		if identityResult.Decision == DecisionExplicitDeny || identityResult.Decision == DecisionAllow && identityDecision == DecisionImplicitDeny {
+			identityDecision = identityResult.Decision
+		}
+	}
+	decisions := []Decision{identityDecision}
+	if policy.PermissionsBoundary != nil {
+		boundaryResult := policy.PermissionsBoundary.IsAllowed("", action, resource, context)
+		result.PermissionsBoundary = &boundaryResult
+		decisions = append(decisions, boundaryResult.Decision)
+	}
+	for _, serviceControlPolicy := range policy.ServiceControlPolicies {
+		scpResult := serviceControlPolicy.IsAllowed("", action, resource, context)
+		result.ServiceControlPolicies = append(result.ServiceControlPolicies, scpResult)
+		decisions = append(decisions, scpResult.Decision)
+	}
+
+	result.Decision = DecisionAllow
+	for _, decision := range decisions {
+		if decision == DecisionExplicitDeny {
+			result.Decision = DecisionExplicitDeny
+			return result
+		}
+		if decision == DecisionImplicitDeny {
+			result.Decision = DecisionImplicitDeny
+		}
+	}
+	return result
+}
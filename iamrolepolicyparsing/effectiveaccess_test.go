@@ -0,0 +1,125 @@
+package iamrolepolicyparsing
+
+import (
+	"reflect"
+	"testing"
+)
+
+func effectivePolicyOf(t *testing.T, identities []string, boundary string, scps []string) EffectivePolicy {
+	var policy EffectivePolicy
+	for _, identity := range identities {
+		pd := policyDocumentOf(t, identity)
+		policy.IdentityPolicies = append(policy.IdentityPolicies, &pd)
+	}
+	if boundary != "" {
+		pd := policyDocumentOf(t, boundary)
+		policy.PermissionsBoundary = &pd
+	}
+	for _, scp := range scps {
+		pd := policyDocumentOf(t, scp)
+		policy.ServiceControlPolicies = append(policy.ServiceControlPolicies, &pd)
+	}
+	return policy
+}
+
+const effectiveIdentity = `{"Version": "2012-10-17", "Statement": [
+	{"Effect": "Allow", "Action": ["s3:*", "ec2:*"], "Resource": "*"}
+]}`
+
+func TestEffectivePolicy_IsAllowedWithoutBoundaryOrScp(t *testing.T) {
+	policy := effectivePolicyOf(t, []string{effectiveIdentity}, "", nil)
+	expected := EffectiveEvaluationResult{Decision: DecisionAllow, IdentityPolicies: []EvaluationResult{{Decision: DecisionAllow, StatementIndexes: []int{0}}}}
+
+	result := policy.IsAllowed("ec2:RunInstances", "*", nil)
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected: %+v, got: %+v", expected, result)
+	}
+}
+
+func TestEffectivePolicy_IsAllowedIntersectsThePolicies(t *testing.T) {
+	boundary := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:*", "iam:*"], "Resource": "*"}]}`
+	scp := `{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Action": "*", "Resource": "*"},
+		{"Effect": "Deny", "Action": "s3:DeleteBucket", "Resource": "*"}
+	]}`
+	policy := effectivePolicyOf(t, []string{effectiveIdentity}, boundary, []string{scp})
+	cases := map[string]Decision{
+		"s3:GetObject":     DecisionAllow,        // allowed by all three
+		"ec2:RunInstances": DecisionImplicitDeny, // not allowed by the boundary
+		"iam:CreateUser":   DecisionImplicitDeny, // the boundary doesn't grant access by itself
+		"s3:DeleteBucket":  DecisionExplicitDeny, // denied by the SCP
+		"dynamodb:Query":   DecisionImplicitDeny,
+	}
+
+	for action, expected := range cases {
+		if result := policy.IsAllowed(action, "arn:aws:s3:::bucket", nil); result.Decision != expected {
+			t.Errorf("%s: Expected: %s, got: %s", action, expected, result.Decision)
+		}
+	}
+}
+
+func TestEffectivePolicy_IsAllowedReportsEveryPolicy(t *testing.T) {
+	scp := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "ec2:*", "Resource": "*"}]}`
+	policy := effectivePolicyOf(t, []string{effectiveIdentity}, "", []string{scp})
+	expected := EffectiveEvaluationResult{
+		Decision:               DecisionImplicitDeny,
+		IdentityPolicies:       []EvaluationResult{{Decision: DecisionAllow, StatementIndexes: []int{0}}},
+		ServiceControlPolicies: []EvaluationResult{{Decision: DecisionImplicitDeny}},
+	}
+
+	result := policy.IsAllowed("s3:GetObject", "arn:aws:s3:::bucket/key", nil)
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected: %+v, got: %+v", expected, result)
+	}
+}
+
+func TestEffectivePolicy_IsAllowedUnitesTheIdentityPolicies(t *testing.T) {
+	dynamodb := `{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Action": "dynamodb:*", "Resource": "*"},
+		{"Effect": "Deny", "Action": "ec2:TerminateInstances", "Resource": "*"}
+	]}`
+	policy := effectivePolicyOf(t, []string{effectiveIdentity, dynamodb}, "", nil)
+	cases := map[string]Decision{
+		"s3:GetObject":           DecisionAllow,        // allowed by the first policy
+		"dynamodb:Query":         DecisionAllow,        // allowed by the second policy
+		"ec2:TerminateInstances": DecisionExplicitDeny, // allowed by the first policy, denied by the second
+		"iam:CreateUser":         DecisionImplicitDeny,
+	}
+
+	for action, expected := range cases {
+		if result := policy.IsAllowed(action, "*", nil); result.Decision != expected {
+			t.Errorf("%s: Expected: %s, got: %s", action, expected, result.Decision)
+		}
+	}
+	if result := policy.IsAllowed("iam:CreateUser", "*", nil); len(result.IdentityPolicies) != 2 {
+		t.Errorf("Expected the results of both identity policies, got: %+v", result.IdentityPolicies)
+	}
+}
+
+func TestEffectivePolicy_IsAllowedIntersectsTheScpLevels(t *testing.T) {
+	root := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "*", "Resource": "*"}]}`
+	ou := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:*", "ec2:*"], "Resource": "*"}]}`
+	account := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:*", "Resource": "*"}]}`
+	policy := effectivePolicyOf(t, []string{effectiveIdentity}, "", []string{root, ou, account})
+	cases := map[string]Decision{
+		"s3:GetObject":     DecisionAllow,        // allowed by every level
+		"ec2:RunInstances": DecisionImplicitDeny, // not allowed by the account level
+	}
+
+	for action, expected := range cases {
+		if result := policy.IsAllowed(action, "*", nil); result.Decision != expected {
+			t.Errorf("%s: Expected: %s, got: %s", action, expected, result.Decision)
+		}
+	}
+}
+
+func TestEffectivePolicy_IsAllowedWithoutIdentityPolicies(t *testing.T) {
+	scp := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "*", "Resource": "*"}]}`
+	policy := effectivePolicyOf(t, nil, "", []string{scp})
+
+	if result := policy.IsAllowed("s3:GetObject", "*", nil); result.Decision != DecisionImplicitDeny {
+		t.Errorf("Expected: %s, got: %s", DecisionImplicitDeny, result.Decision)
+	}
+}
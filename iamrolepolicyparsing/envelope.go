@@ -0,0 +1,250 @@
+package iamrolepolicyparsing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+/**
+ * Envelope names the CloudFormation resource type whose properties a policy JSON holds, see DetectEnvelope.
+ * A bare policy document has no envelope, it is named after what it is used as instead
+ * (EnvelopeTrustPolicy, EnvelopeResourcePolicy or EnvelopePolicyDocument).
+ */
+type Envelope string
+
+const (
+	EnvelopeRolePolicy    Envelope = "AWS::IAM::RolePolicy"
+	EnvelopeManagedPolicy Envelope = "AWS::IAM::ManagedPolicy"
+	EnvelopePolicy        Envelope = "AWS::IAM::Policy"
+	EnvelopeUserPolicy    Envelope = "AWS::IAM::UserPolicy"
+	EnvelopeGroupPolicy   Envelope = "AWS::IAM::GroupPolicy"
+	EnvelopeBucketPolicy  Envelope = "AWS::S3::BucketPolicy"
+	EnvelopeQueuePolicy   Envelope = "AWS::SQS::QueuePolicy"
+	EnvelopeTopicPolicy   Envelope = "AWS::SNS::TopicPolicy"
+
+	EnvelopeTrustPolicy    Envelope = "AssumeRolePolicyDocument"
+	EnvelopeResourcePolicy Envelope = "ResourcePolicy"
+	EnvelopePolicyDocument Envelope = "PolicyDocument"
+)
+
+/**
+ * Guesses the envelope of a policy from the keys of its JSON object:
+ *
+ *   UserName                                      - EnvelopeUserPolicy
+ *   GroupName                                     - EnvelopeGroupPolicy
+ *   RoleName                                      - EnvelopeRolePolicy
+ *   Bucket, Queues, Topics                        - EnvelopeBucketPolicy, EnvelopeQueuePolicy, EnvelopeTopicPolicy
+ *   ManagedPolicyName, Description, Path          - EnvelopeManagedPolicy
+ *   Groups, Roles or Users                        - EnvelopePolicy with a PolicyName, EnvelopeManagedPolicy without
+ *   Version or Statement, without PolicyDocument  - a bare policy document:
+ *                                                   EnvelopeTrustPolicy if it names principals and only grants
+ *                                                   sts:AssumeRole* and sts:TagSession, EnvelopeResourcePolicy if it
+ *                                                   names other principals, EnvelopePolicyDocument otherwise
+ *
+ * Anything else, including data that is not a JSON object, is taken for EnvelopeRolePolicy,
+ * so that parsing it reports what is wrong with it as a role policy (e.g. a PolicyDocument without a PolicyName).
+ */
+func DetectEnvelope(data []byte) Envelope {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return EnvelopeRolePolicy
+	}
+	has := func(keys ...string) bool {
+		for _, key := range keys {
+			if _, ok := m[key]; ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case has("UserName"):
+		return EnvelopeUserPolicy
+	case has("GroupName"):
+		return EnvelopeGroupPolicy
+	case has("RoleName"):
+		return EnvelopeRolePolicy
+	case has("Bucket"):
+		return EnvelopeBucketPolicy
+	case has("Queues"):
+		return EnvelopeQueuePolicy
+	case has("Topics"):
+		return EnvelopeTopicPolicy
+	case has("ManagedPolicyName", "Description", "Path"):
+		return EnvelopeManagedPolicy
+	case has("Groups", "Roles", "Users") && has("PolicyName"):
+		return EnvelopePolicy
+	case has("Groups", "Roles", "Users"):
+		return EnvelopeManagedPolicy
+	case has("Version", "Statement") && !has("PolicyDocument", "PolicyName"):
+		return detectDocumentEnvelope(data)
+	}
+	return EnvelopeRolePolicy
+}
+
+// Tells the kinds of bare policy documents apart by their statements, see DetectEnvelope
+func detectDocumentEnvelope(data []byte) Envelope {
+	var pd PolicyDocument
+	// the errors are reported when the document is parsed as the detected kind
+	pd.unmarshal(data)
+
+	hasPrincipal, onlyTrustActions := false, true
+	for _, statement := range pd.StatementList() {
+		hasPrincipal = hasPrincipal || statement.PrincipalValue != nil
+		if statement.ActionValue != nil && !statement.Action {
+			onlyTrustActions = false
+		}
+		for _, actionString := range statement.actionStrings() {
+			action, err := ParseAction(actionString)
+			onlyTrustActions = onlyTrustActions && err == nil && isTrustAction(action)
+		}
+	}
+
+	switch {
+	case hasPrincipal && onlyTrustActions:
+		return EnvelopeTrustPolicy
+	case hasPrincipal:
+		return EnvelopeResourcePolicy
+	}
+	return EnvelopePolicyDocument
+}
+
+/**
+ * Parses data as the policy type of its envelope (see DetectEnvelope), returning the envelope,
+ * the policy document for the policy checks (nil if there was none) and every error found, like Validate.
+ */
+func ValidateEnvelope(data []byte) (Envelope, *PolicyDocument, []*ParseError) {
+	envelope := DetectEnvelope(data)
+	switch envelope {
+	case EnvelopeManagedPolicy:
+		var policy IamManagedPolicy
+		parseErrors := policy.Validate(data)
+		return envelope, policy.PolicyDocument, parseErrors
+	case EnvelopePolicy:
+		var policy IamPolicy
+		parseErrors := policy.Validate(data)
+		return envelope, policy.PolicyDocument, parseErrors
+	case EnvelopeUserPolicy:
+		var policy IamUserPolicy
+		parseErrors := policy.Validate(data)
+		return envelope, policy.PolicyDocument, parseErrors
+	case EnvelopeGroupPolicy:
+		var policy IamGroupPolicy
+		parseErrors := policy.Validate(data)
+		return envelope, policy.PolicyDocument, parseErrors
+	case EnvelopeBucketPolicy:
+		var policy BucketPolicy
+		parseErrors := policy.Validate(data)
+		return envelope, policy.PolicyDocument, parseErrors
+	case EnvelopeQueuePolicy:
+		var policy QueuePolicy
+		parseErrors := policy.Validate(data)
+		return envelope, policy.PolicyDocument, parseErrors
+	case EnvelopeTopicPolicy:
+		var policy TopicPolicy
+		parseErrors := policy.Validate(data)
+		return envelope, policy.PolicyDocument, parseErrors
+	case EnvelopeTrustPolicy:
+		var policy TrustPolicy
+		parseErrors := policy.Validate(data)
+		return envelope, policy.PolicyDocument, parseErrors
+	case EnvelopeResourcePolicy:
+		var policy ResourcePolicy
+		parseErrors := policy.Validate(data)
+		return envelope, policy.PolicyDocument, parseErrors
+	case EnvelopePolicyDocument:
+		// without principals, a bare document is taken for an identity policy
+		var policyDocument PolicyDocument
+		parseErrors := policyDocument.ValidateKind(data, PolicyKindIdentity)
+		return envelope, &policyDocument, parseErrors
+	}
+	var policy IamRolePolicy
+	parseErrors := policy.Validate(data)
+	return EnvelopeRolePolicy, policy.PolicyDocument, parseErrors
+}
+
+// envelopeField struct describes a key of an envelope besides "PolicyDocument",
+// whose value is unmarshalled into target (a **string or a *[]string)
+type envelopeField struct {
+	key      string
+	target   interface{}
+	required bool
+}
+
+// Parses an envelope object: its policy document, checked against the kind and by documentErrors (if not nil),
+// goes to document and the other keys to their fields. Returns every error found.
+func unmarshalEnvelope(data []byte, document **PolicyDocument, kind PolicyKind, documentErrors func(*PolicyDocument) []error, fields ...envelopeField) []error {
+	// null is a no-op, see PolicyDocument.unmarshal
+	if string(data) == "null" {
+		return nil
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return []error{jsonParseError("", err)}
+	}
+
+	var errs []error
+	// Ensure no unwanted properties exist in data
+	for _, key := range sortedKeys(m) {
+		if key != "PolicyDocument" && !hasEnvelopeField(fields, key) {
+			errs = append(errs, newParseError(CodeUnknownKey, jsonPointer("", key), fmt.Sprintf("unknown key: %s", key)))
+		}
+	}
+
+	// the policy document is parsed first, so that its errors are reported first
+	if !isAbsentOrNull(m, "PolicyDocument") {
+		documentErrs := unmarshalDocument(bytes.TrimSpace(m["PolicyDocument"]), document, kind, documentErrors)
+		errs = append(errs, prefixErrorPaths("/PolicyDocument", documentErrs)...)
+	}
+	for _, field := range fields {
+		if isAbsentOrNull(m, field.key) {
+			continue
+		}
+		if err := json.Unmarshal(m[field.key], field.target); err != nil {
+			expected := "an array of strings"
+			if _, ok := field.target.(**string); ok {
+				expected = "a string"
+			}
+			errs = append(errs, newParseError(CodeInvalidType, jsonPointer("", field.key), fmt.Sprintf("%s should be %s", field.key, expected)))
+		}
+	}
+
+	if isAbsentOrNull(m, "PolicyDocument") {
+		errs = append(errs, newParseError(CodeMissingKey, "/PolicyDocument", "PolicyDocument is required"))
+	}
+	for _, field := range fields {
+		if field.required && isAbsentOrNull(m, field.key) {
+			errs = append(errs, newParseError(CodeMissingKey, jsonPointer("", field.key), fmt.Sprintf("%s is required", field.key)))
+		}
+	}
+
+	return errs
+}
+
+// Parses a policy document that is not wrapped in an envelope (TrustPolicy, ResourcePolicy, KeyPolicy) into document,
+// checking it against the kind and by documentErrors (if not nil). Returns every error found.
+func unmarshalDocument(data []byte, document **PolicyDocument, kind PolicyKind, documentErrors func(*PolicyDocument) []error) []error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var policyDocument PolicyDocument
+	errs := policyDocument.unmarshalKind(data, kind)
+	if documentErrors != nil {
+		errs = append(errs, documentErrors(&policyDocument)...)
+	}
+	*document = &policyDocument
+	return errs
+}
+
+func hasEnvelopeField(fields []envelopeField, key string) bool {
+	for _, field := range fields {
+		if field.key == key {
+			return true
+		}
+	}
+	return false
+}
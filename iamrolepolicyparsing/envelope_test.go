@@ -0,0 +1,85 @@
+package iamrolepolicyparsing
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDetectEnvelope(t *testing.T) {
+	cases := map[string]Envelope{
+		`{"PolicyName": "a", "PolicyDocument": {}}`:                                                                       EnvelopeRolePolicy,
+		`{"PolicyDocument": {}}`:                                                                                          EnvelopeRolePolicy,
+		`{"RoleName": "a", "PolicyDocument": {}}`:                                                                         EnvelopeRolePolicy,
+		`{"ManagedPolicyName": "a", "PolicyDocument": {}}`:                                                                EnvelopeManagedPolicy,
+		`{"Roles": ["a"], "PolicyDocument": {}}`:                                                                          EnvelopeManagedPolicy,
+		`{"PolicyName": "a", "Roles": ["a"], "PolicyDocument": {}}`:                                                       EnvelopePolicy,
+		`{"PolicyName": "a", "UserName": "a", "PolicyDocument": {}}`:                                                      EnvelopeUserPolicy,
+		`{"PolicyName": "a", "GroupName": "a", "PolicyDocument": {}}`:                                                     EnvelopeGroupPolicy,
+		`{"Bucket": "a", "PolicyDocument": {}}`:                                                                           EnvelopeBucketPolicy,
+		`{"Queues": ["a"], "PolicyDocument": {}}`:                                                                         EnvelopeQueuePolicy,
+		`{"Topics": ["a"], "PolicyDocument": {}}`:                                                                         EnvelopeTopicPolicy,
+		`{"Version": "2012-10-17", "Statement": []}`:                                                                      EnvelopePolicyDocument,
+		`{"Statement": {"Effect": "Allow", "Action": "s3:*", "Resource": "*"}}`:                                           EnvelopePolicyDocument,
+		`{"Statement": [{"Effect": "Allow", "Principal": {"Service": "ec2.amazonaws.com"}, "Action": "sts:AssumeRole"}]}`: EnvelopeTrustPolicy,
+		`{"Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "*"}]}`:               EnvelopeResourcePolicy,
+		`{"Statement": [{"Effect": "Allow", "Principal": "*", "NotAction": "s3:*"}]}`:                                     EnvelopeResourcePolicy,
+		`{"PolicyName": "a", "Statement": []}`:                                                                            EnvelopeRolePolicy,
+		`not a json`:                                                                                                      EnvelopeRolePolicy,
+	}
+
+	for data, expected := range cases {
+		if envelope := DetectEnvelope([]byte(data)); envelope != expected {
+			t.Errorf("%s: Expected: %s, got: %s", data, expected, envelope)
+		}
+	}
+}
+
+func TestValidateEnvelope(t *testing.T) {
+	data := `{"PolicyName": "name", "UserName": "JohnDoe", "PolicyDocument": ` + identityPolicyDocument + `}`
+
+	envelope, document, parseErrors := ValidateEnvelope([]byte(data))
+
+	if envelope != EnvelopeUserPolicy || parseErrors != nil {
+		t.Errorf("Expected: %s without errors, got: %s with %v", EnvelopeUserPolicy, envelope, parseErrors)
+	}
+	if document == nil || len(document.StatementsWithWildcardResource()) != 1 {
+		t.Errorf("Expected the policy document with one wildcard resource, got: %v", document)
+	}
+}
+
+func TestValidateEnvelope_BareDocuments(t *testing.T) {
+	cases := map[string][]string{
+		`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"}]}`: nil,
+		`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "sts:AssumeRole", "Resource": "*"}]}`:                   {"/Statement/0/Resource: Resource is not allowed in a trust policy"},
+		`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*", "Comment": "x"}]}`:                       {"/Statement/0/Comment: unknown key: Comment"},
+	}
+
+	for data, expected := range cases {
+		_, document, parseErrors := ValidateEnvelope([]byte(data))
+		var messages []string
+		for _, parseError := range parseErrors {
+			messages = append(messages, parseError.Error())
+		}
+		if !reflect.DeepEqual(messages, expected) {
+			t.Errorf("%s: Expected: %q, got: %q", data, expected, messages)
+		}
+		if len(document.StatementList()) != 1 {
+			t.Errorf("%s: Expected the document to be parsed, got: %v", data, document)
+		}
+	}
+}
+
+func TestValidateEnvelope_MissingPolicyName(t *testing.T) {
+	data, err := os.ReadFile("../example-jsons/missing-policy-name.json")
+	if err != nil {
+		t.Fatalf("Error reading file: %v", err)
+	}
+	expected := []*ParseError{{Code: CodeMissingKey, Path: "/PolicyName", Message: "PolicyName is required", Line: 1, Column: 1}}
+
+	envelope, _, parseErrors := ValidateEnvelope(data)
+
+	if envelope != EnvelopeRolePolicy || !reflect.DeepEqual(parseErrors, expected) {
+		t.Errorf("Expected: %s with %v, got: %s with %v", EnvelopeRolePolicy, expected, envelope, parseErrors)
+	}
+}
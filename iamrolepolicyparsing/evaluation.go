@@ -0,0 +1,80 @@
+package iamrolepolicyparsing
+
+import "fmt"
+
+/**
+ * Decision is the outcome of evaluating a policy for a request.
+ *
+ * DecisionImplicitDeny means that no statement allowed the request, DecisionAllow that at least one "Allow" statement
+ * matched and no "Deny" statement did, and DecisionExplicitDeny that a "Deny" statement matched.
+ */
+type Decision int
+
+const (
+	DecisionImplicitDeny Decision = iota
+	DecisionAllow
+	DecisionExplicitDeny
+)
+
+func (decision Decision) String() string {
+	switch decision {
+	case DecisionImplicitDeny:
+		return "ImplicitDeny"
+	case DecisionAllow:
+		return "Allow"
+	case DecisionExplicitDeny:
+		return "ExplicitDeny"
+	}
+	return fmt.Sprintf("Decision(%d)", int(decision))
+}
+
+/**
+ * RequestContext holds the condition keys of a request (e.g. "aws:SourceIp") and their values.
+ * Single-valued keys have a single element.
+ */
+type RequestContext map[string][]string
+
+/**
+ * EvaluationResult struct holds the Decision for a request and the indexes of the statements that determined it:
+ * the matching "Deny" statements for DecisionExplicitDeny, the matching "Allow" statements for DecisionAllow
+ * and none for DecisionImplicitDeny.
+ */
+type EvaluationResult struct {
+	Decision         Decision
+	StatementIndexes []int
+}
+
+/**
+ * Evaluates the policy document for a request in the way AWS evaluates a single policy:
+ * an explicit deny overrides any allow, and a request that no statement allows is implicitly denied.
+ *
+ * principal is the principal id making the request ("" to skip the Principal check), action the action name
+ * (e.g. "s3:PutObject"), resource the ARN of the resource and context the condition keys of the request.
+ * NotAction, NotResource and NotPrincipal are honored, and a statement only applies if its conditions are matched
+ * (see conditionevaluation.go). Statements built in code are evaluated from their raw values (ActionValue,
+ * ResourceValue, ...) like parsed ones.
+ */
+func (pd *PolicyDocument) IsAllowed(principal string, action string, resource string, context RequestContext) EvaluationResult {
+	var allowing, denying []int
+	for i, statement := range pd.StatementList() {
+		if !statement.MatchesPrincipal(principal) || !statement.MatchesAction(action) || !statement.MatchesResourceInContext(resource, context) {
+			continue
+		}
+		if !statement.MatchesConditions(context) {
+			continue
+		}
+		if statement.Effect != nil && *statement.Effect == "Deny" {
+			denying = append(denying, i)
+		} else {
+			allowing = append(allowing, i)
+		}
+	}
+
+	if len(denying) > 0 {
+		return EvaluationResult{Decision: DecisionExplicitDeny, StatementIndexes: denying}
+	}
+	if len(allowing) > 0 {
+		return EvaluationResult{Decision: DecisionAllow, StatementIndexes: allowing}
+	}
+	return EvaluationResult{Decision: DecisionImplicitDeny}
+}
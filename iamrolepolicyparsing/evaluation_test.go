@@ -0,0 +1,172 @@
+package iamrolepolicyparsing
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func policyDocumentOf(t *testing.T, data string) PolicyDocument {
+	var pd PolicyDocument
+	if err := json.Unmarshal([]byte(data), &pd); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+	return pd
+}
+
+func TestPolicyDocument_IsAllowedWhenAllowed(t *testing.T) {
+	pd := policyDocumentOf(t, `{"Version":"2012-10-17","Statement":[
+		{"Sid":"Read","Effect":"Allow","Action":"s3:Get*","Resource":"arn:aws:s3:::bucket/*"},
+		{"Sid":"Write","Effect":"Allow","Action":"s3:PutObject","Resource":"arn:aws:s3:::bucket/*"}
+	]}`)
+	expected := EvaluationResult{Decision: DecisionAllow, StatementIndexes: []int{1}}
+
+	result := pd.IsAllowed("", "s3:PutObject", "arn:aws:s3:::bucket/key", nil)
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected: %+v, got: %+v", expected, result)
+	}
+}
+
+func TestPolicyDocument_IsAllowedWhenImplicitlyDenied(t *testing.T) {
+	pd := policyDocumentOf(t, `{"Version":"2012-10-17","Statement":[
+		{"Effect":"Allow","Action":"s3:Get*","Resource":"arn:aws:s3:::bucket/*"}
+	]}`)
+	expected := EvaluationResult{Decision: DecisionImplicitDeny}
+
+	result := pd.IsAllowed("", "s3:PutObject", "arn:aws:s3:::bucket/key", nil)
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected: %+v, got: %+v", expected, result)
+	}
+}
+
+func TestPolicyDocument_IsAllowedWhenExplicitlyDenied(t *testing.T) {
+	pd := policyDocumentOf(t, `{"Version":"2012-10-17","Statement":[
+		{"Effect":"Allow","Action":"s3:*","Resource":"*"},
+		{"Effect":"Deny","Action":"s3:DeleteObject","Resource":"arn:aws:s3:::bucket/*"}
+	]}`)
+	expected := EvaluationResult{Decision: DecisionExplicitDeny, StatementIndexes: []int{1}}
+
+	result := pd.IsAllowed("", "s3:DeleteObject", "arn:aws:s3:::bucket/key", nil)
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected: %+v, got: %+v", expected, result)
+	}
+}
+
+func TestPolicyDocument_IsAllowedWithNotActionAndNotResource(t *testing.T) {
+	pd := policyDocumentOf(t, `{"Version":"2012-10-17","Statement":[
+		{"Effect":"Allow","NotAction":"iam:*","NotResource":"arn:aws:s3:::secret/*"}
+	]}`)
+
+	if result := pd.IsAllowed("", "s3:GetObject", "arn:aws:s3:::bucket/key", nil); result.Decision != DecisionAllow {
+		t.Errorf("Expected: %v, got: %v", DecisionAllow, result.Decision)
+	}
+	if result := pd.IsAllowed("", "iam:CreateUser", "arn:aws:iam::123456789012:user/x", nil); result.Decision != DecisionImplicitDeny {
+		t.Errorf("Expected: %v, got: %v", DecisionImplicitDeny, result.Decision)
+	}
+	if result := pd.IsAllowed("", "s3:GetObject", "arn:aws:s3:::secret/key", nil); result.Decision != DecisionImplicitDeny {
+		t.Errorf("Expected: %v, got: %v", DecisionImplicitDeny, result.Decision)
+	}
+}
+
+func TestPolicyDocument_IsAllowedWithPrincipal(t *testing.T) {
+	pd := policyDocumentOf(t, `{"Version":"2012-10-17","Statement":[
+		{"Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:root"]},"Action":"s3:GetObject","Resource":"*"}
+	]}`)
+
+	if result := pd.IsAllowed("arn:aws:iam::123456789012:role/Reader", "s3:GetObject", "arn:aws:s3:::bucket/key", nil); result.Decision != DecisionAllow {
+		t.Errorf("Expected: %v, got: %v", DecisionAllow, result.Decision)
+	}
+	if result := pd.IsAllowed("arn:aws:iam::210987654321:role/Reader", "s3:GetObject", "arn:aws:s3:::bucket/key", nil); result.Decision != DecisionImplicitDeny {
+		t.Errorf("Expected: %v, got: %v", DecisionImplicitDeny, result.Decision)
+	}
+}
+
+func TestPolicyDocument_IsAllowedWithConditions(t *testing.T) {
+	pd := policyDocumentOf(t, `{"Version":"2012-10-17","Statement":[
+		{"Effect":"Allow","Action":"s3:GetObject","Resource":"*","Condition":{"IpAddress":{"aws:SourceIp":"10.0.0.0/8"}}},
+		{"Effect":"Deny","Action":"s3:*","Resource":"*","Condition":{"Bool":{"aws:SecureTransport":"false"}}}
+	]}`)
+	tests := []struct {
+		context  RequestContext
+		expected EvaluationResult
+	}{
+		{RequestContext{"aws:SourceIp": {"10.1.2.3"}, "aws:SecureTransport": {"true"}}, EvaluationResult{Decision: DecisionAllow, StatementIndexes: []int{0}}},
+		{RequestContext{"aws:SourceIp": {"192.168.0.1"}, "aws:SecureTransport": {"true"}}, EvaluationResult{Decision: DecisionImplicitDeny}},
+		{RequestContext{"aws:SourceIp": {"10.1.2.3"}, "aws:SecureTransport": {"false"}}, EvaluationResult{Decision: DecisionExplicitDeny, StatementIndexes: []int{1}}},
+		{nil, EvaluationResult{Decision: DecisionImplicitDeny}},
+	}
+
+	for _, test := range tests {
+		result := pd.IsAllowed("", "s3:GetObject", "arn:aws:s3:::bucket/key", test.context)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%v: Expected: %+v, got: %+v", test.context, test.expected, result)
+		}
+	}
+}
+
+func TestPolicyDocument_IsAllowedWithStatementsBuiltInCode(t *testing.T) {
+	stringOf := func(s string) *string { return &s }
+	pd := PolicyDocument{
+		Version: stringOf("2012-10-17"),
+		Statements: &[]Statement{
+			{Effect: stringOf("Allow"), Action: true, Resource: true, ActionValue: "*", ResourceValue: "*"},
+			{
+				Effect:        stringOf("Deny"),
+				Action:        true,
+				Resource:      true,
+				ActionValue:   []interface{}{"s3:DeleteBucket"},
+				ResourceValue: "*",
+				ConditionMap:  map[string]interface{}{"StringEquals": map[string]interface{}{"aws:RequestedRegion": "eu-west-1"}},
+			},
+		},
+	}
+	inRegion := RequestContext{"aws:RequestedRegion": {"eu-west-1"}}
+	cases := []struct {
+		action   string
+		context  RequestContext
+		expected EvaluationResult
+	}{
+		{"s3:GetObject", nil, EvaluationResult{Decision: DecisionAllow, StatementIndexes: []int{0}}},
+		{"s3:DeleteBucket", nil, EvaluationResult{Decision: DecisionAllow, StatementIndexes: []int{0}}},
+		{"s3:DeleteBucket", inRegion, EvaluationResult{Decision: DecisionExplicitDeny, StatementIndexes: []int{1}}},
+	}
+
+	for _, test := range cases {
+		if result := pd.IsAllowed("", test.action, "arn:aws:s3:::bucket", test.context); !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("%s %v: Expected: %+v, got: %+v", test.action, test.context, test.expected, result)
+		}
+	}
+}
+
+func TestIamRolePolicy_IsAllowed(t *testing.T) {
+	data := `{"PolicyName": "policyName", "PolicyDocument":{"Version": "2012-10-17", "Statement":[{"Effect":"Allow","Action":"s3:PutObject","Resource":"arn:aws:s3:::bucket/*"}]}}`
+	var policy IamRolePolicy
+	if err := json.Unmarshal([]byte(data), &policy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+	expected := EvaluationResult{Decision: DecisionAllow, StatementIndexes: []int{0}}
+
+	result := policy.IsAllowed("", "s3:PutObject", "arn:aws:s3:::bucket/key", nil)
+
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected: %+v, got: %+v", expected, result)
+	}
+}
+
+func TestDecision_String(t *testing.T) {
+	tests := map[Decision]string{
+		DecisionImplicitDeny: "ImplicitDeny",
+		DecisionAllow:        "Allow",
+		DecisionExplicitDeny: "ExplicitDeny",
+		Decision(7):          "Decision(7)",
+	}
+
+	for decision, expected := range tests {
+		if decision.String() != expected {
+			t.Errorf("Expected: %s, got: %s", expected, decision.String())
+		}
+	}
+}
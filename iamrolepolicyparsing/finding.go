@@ -0,0 +1,58 @@
+package iamrolepolicyparsing
+
+import "fmt"
+
+/**
+ * StatementFinding struct points at a value inside a statement that was flagged by one of the policy checks.
+ *
+ * StatementIndex is the index of the statement in the policy document and Sid is its Sid (nil if absent).
+ * ElementIndex is the index of the flagged value inside the Action/Resource array (or principal list, see trustpolicy.go),
+ * 0 if the value was a single string.
+ * Value is the flagged value itself.
+ * If a check flags the statement as a whole, ElementIndex is -1 and Value names the offending key (e.g. "NotResource").
+ */
+type StatementFinding struct {
+	StatementIndex int
+	Sid            *string
+	ElementIndex   int
+	Value          string
+}
+
+func (this StatementFinding) String() string {
+	sid := "nil"
+	if this.Sid != nil {
+		sid = *this.Sid
+	}
+	return fmt.Sprintf("statement %d (Sid: %s), element %d: %s", this.StatementIndex, sid, this.ElementIndex, this.Value)
+}
+
+// Builds a finding for every element of every statement that elementIndexes flags, taking the flagged values from elementValues
+func findStatementElements(statements []Statement, elementIndexes func(Statement) []int, elementValues func(Statement) []string) []StatementFinding {
+	var findings []StatementFinding
+	for i, statement := range statements {
+		values := elementValues(statement)
+		for _, elementIndex := range elementIndexes(statement) {
+			if elementIndex >= len(values) {
+				continue
+			}
+			findings = append(findings, StatementFinding{
+				StatementIndex: i,
+				Sid:            statement.Sid,
+				ElementIndex:   elementIndex,
+				Value:          values[elementIndex],
+			})
+		}
+	}
+	return findings
+}
+
+// Returns the statements with the "Allow" effect, keeping the rest as zero values so that statement indexes stay intact
+func allowStatements(statements []Statement) []Statement {
+	allowed := make([]Statement, len(statements))
+	for i, statement := range statements {
+		if statement.Effect != nil && *statement.Effect == "Allow" {
+			allowed[i] = statement
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,76 @@
+package iamrolepolicyparsing
+
+import "unicode"
+
+// A character of a wildcard pattern. Literal characters never act as wildcards,
+// which is how substituted policy variables and the ${*}, ${?} and ${$} escapes are matched.
+type patternRune struct {
+	r       rune
+	literal bool
+}
+
+// Turns a pattern string into pattern characters, where every '*' and '?' is a wildcard
+func toPattern(pattern string) []patternRune {
+	var runes []patternRune
+	for _, r := range pattern {
+		runes = append(runes, patternRune{r: r})
+	}
+	return runes
+}
+
+// Turns a string into pattern characters that only match themselves
+func toLiteralPattern(s string) []patternRune {
+	var runes []patternRune
+	for _, r := range s {
+		runes = append(runes, patternRune{r: r, literal: true})
+	}
+	return runes
+}
+
+/**
+ * Returns whether s matches the pattern, where '*' matches any sequence of characters (including none)
+ * and '?' matches any single character. This is the wildcard syntax used by actions, resources and
+ * the *Like condition operators.
+ */
+func globMatch(pattern string, s string, ignoreCase bool) bool {
+	return patternMatch(toPattern(pattern), s, ignoreCase)
+}
+
+func patternMatch(pattern []patternRune, s string, ignoreCase bool) bool {
+	r := []rune(s)
+	isStar := func(i int) bool { return !pattern[i].literal && pattern[i].r == '*' }
+	matchesRune := func(i int, c rune) bool {
+		if !pattern[i].literal && pattern[i].r == '?' {
+			return true
+		}
+		if ignoreCase {
+			return unicode.ToLower(pattern[i].r) == unicode.ToLower(c)
+		}
+		return pattern[i].r == c
+	}
+
+	// position in the pattern right after the last '*' seen, and the position in s it was matched against
+	starIndex, matchIndex := -1, 0
+	i, j := 0, 0
+	for j < len(r) {
+		if i < len(pattern) && !isStar(i) && matchesRune(i, r[j]) {
+			i++
+			j++
+		} else if i < len(pattern) && isStar(i) {
+			starIndex = i + 1
+			matchIndex = j
+			i++
+		} else if starIndex != -1 {
+			// let the last '*' consume one more character
+			matchIndex++
+			i = starIndex
+			j = matchIndex
+		} else {
+			return false
+		}
+	}
+	for i < len(pattern) && isStar(i) {
+		i++
+	}
+	return i == len(pattern)
+}
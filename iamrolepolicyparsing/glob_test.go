@@ -0,0 +1,34 @@
+package iamrolepolicyparsing
+
+import "testing"
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern    string
+		s          string
+		ignoreCase bool
+		expected   bool
+	}{
+		{"*", "", false, true},
+		{"*", "anything", false, true},
+		{"Get*", "GetObject", false, true},
+		{"Get*", "PutObject", false, false},
+		{"*Object", "GetObject", false, true},
+		{"Get?bject", "GetObject", false, true},
+		{"Get?bject", "GetOObject", false, false},
+		{"a*b*c", "aXXbYYc", false, true},
+		{"a*b*c", "aXXbYY", false, false},
+		{"get*", "GetObject", false, false},
+		{"get*", "GetObject", true, true},
+		{"exact", "exact", false, true},
+		{"exact", "exactly", false, false},
+		{"", "", false, true},
+		{"**", "x", false, true},
+	}
+
+	for _, test := range tests {
+		if globMatch(test.pattern, test.s, test.ignoreCase) != test.expected {
+			t.Errorf("globMatch(%q, %q, %v): Expected: %v, got: %v", test.pattern, test.s, test.ignoreCase, test.expected, !test.expected)
+		}
+	}
+}
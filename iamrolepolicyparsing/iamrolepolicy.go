@@ -2,22 +2,27 @@ package iamrolepolicyparsing
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
 )
 
 /**
  * IamRolePolicy struct represents a policy in an IAM role.
  *
+ * RoleName is the role the policy is embedded in. CloudFormation requires it in an AWS::IAM::RolePolicy,
+ * but it is optional here so that a bare {"PolicyName": ..., "PolicyDocument": ...} object is still a valid policy.
+ *
  * for grammar see https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_grammar.html
  * see policydocument.go for the PolicyDocument struct
  */
 type IamRolePolicy struct {
 	PolicyDocument *PolicyDocument `json:"PolicyDocument"`
 	PolicyName     *string         `json:"PolicyName"`
+	RoleName       *string         `json:"RoleName"`
 }
 
 func (this IamRolePolicy) String() string {
+	if this.RoleName != nil {
+		return "IamRolePolicy{PolicyDocument: " + this.PolicyDocument.String() + ", PolicyName: " + *this.PolicyName + ", RoleName: " + *this.RoleName + "}"
+	}
 	return "IamRolePolicy{PolicyDocument: " + this.PolicyDocument.String() + ", PolicyName: " + *this.PolicyName + "}"
 }
 
@@ -27,62 +32,119 @@ func (this IamRolePolicy) Equals(other interface{}) bool {
 		return false
 	}
 	return (this.PolicyName == that.PolicyName || *this.PolicyName == *this.PolicyName) &&
+		(this.RoleName == that.RoleName || this.RoleName != nil && that.RoleName != nil && *this.RoleName == *that.RoleName) &&
 		(this.PolicyDocument == that.PolicyDocument || (*this.PolicyDocument).Equals(*that.PolicyDocument))
 }
 
+/**
+ * Parses a {"PolicyName": ..., "PolicyDocument": ...} object, with an optional "RoleName".
+ *
+ * Errors are *ParseError values (see parseerror.go) with paths such as "/PolicyDocument/Statement/3/Principal".
+ * The policy document is parsed before the policy name, so that its errors are reported first.
+ * Only the first error is returned, see Validate for getting all of them.
+ * Unknown keys are errors, see ParseIamRolePolicy for parsing them leniently.
+ * A role policy is an identity policy, so Principal and NotPrincipal are errors (see policykind.go).
+ */
 func (policy *IamRolePolicy) UnmarshalJSON(data []byte) error {
-	// decode.go/line 117
-	// By convention, to approximate the behavior of [Unmarshal] itself,
-	// Unmarshalers implement UnmarshalJSON([]byte("null")) as a no-op.
-	if string(data) == "null" {
-		return nil
-	}
+	return firstError(checkSource(data, policy.unmarshal(data)))
+}
 
-	var statMap map[string]interface{}
-	err := json.Unmarshal(data, &statMap)
-	if err != nil {
-		return err
-	}
+/**
+ * Parses the policy like UnmarshalJSON, but instead of stopping at the first error it walks the whole policy
+ * and returns every error found, in document order within each level. Returns nil if the policy is valid.
+ * Every error has the line and column of the offending value in data.
+ *
+ * The parts of the policy that could be parsed are set even if there are errors,
+ * e.g. a statement with an invalid resource still has its actions.
+ */
+func (policy *IamRolePolicy) Validate(data []byte) []*ParseError {
+	return toParseErrors(checkSource(data, policy.unmarshal(data)))
+}
 
-	// Ensure no unwanted properties exist in data
-	for key, _ := range statMap {
-		if key != "PolicyName" && key != "PolicyDocument" {
-			// todo test
-			return errors.New(fmt.Sprintf("unknown key: %s", key))
-		}
-	}
+// Parses the policy, returning every error found
+func (policy *IamRolePolicy) unmarshal(data []byte) []error {
+	return unmarshalEnvelope(data, &policy.PolicyDocument, PolicyKindIdentity, nil,
+		envelopeField{key: "PolicyName", target: &policy.PolicyName, required: true},
+		envelopeField{key: "RoleName", target: &policy.RoleName},
+	)
+}
 
-	// Unmarshal the JSON using the default Unmarshaler
-	type Alias IamRolePolicy
-	aux := &struct {
-		*Alias
+/**
+ * Writes the policy back as a {"PolicyName": ..., "RoleName": ..., "PolicyDocument": ...} object.
+ * Absent values are omitted.
+ */
+func (policy IamRolePolicy) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		PolicyName     *string         `json:"PolicyName,omitempty"`
+		RoleName       *string         `json:"RoleName,omitempty"`
+		PolicyDocument *PolicyDocument `json:"PolicyDocument,omitempty"`
 	}{
-		Alias: (*Alias)(policy),
-	}
-	if err := json.Unmarshal(data, &aux); err != nil {
-		return errors.New(fmt.Sprintf("error unmarshalling a policy: %s", err.Error()))
+		PolicyName:     policy.PolicyName,
+		RoleName:       policy.RoleName,
+		PolicyDocument: policy.PolicyDocument,
 	}
+	return json.Marshal(aux)
+}
 
-	if policy.PolicyDocument == nil {
-		return errors.New("PolicyDocument is required")
-	}
-	if policy.PolicyName == nil {
-		return errors.New("PolicyName is required")
-	}
+/**
+ * Returns whether no statement in the policy has a resource that is a wildcard ('*').
+ *
+ * see (pd *PolicyDocument)NoStatementHasWildcardResource() bool in policydocument.go
+ */
+func (policy IamRolePolicy) NoStatementHasWildcardResource() bool {
+	return policy.PolicyDocument.NoStatementHasWildcardResource()
+}
 
-	return nil
+/**
+ * Returns a finding for every Resource value in the policy that is a wildcard ('*').
+ *
+ * see (pd *PolicyDocument)StatementsWithWildcardResource() []StatementFinding in policydocument.go
+ */
+func (policy IamRolePolicy) StatementsWithWildcardResource() []StatementFinding {
+	return policy.PolicyDocument.StatementsWithWildcardResource()
 }
 
 /**
- * Returns whether the policy has a statement with a resource that is a wildcard ('*').
+ * Returns a finding for every Resource value of an "Allow" statement that is broader than the given limit.
  *
- * see (stat Statement)isResourceAWildcard() bool in statement.go
+ * see (pd *PolicyDocument)StatementsWithResourceBroaderThan(limit ResourceBreadth) []StatementFinding in policydocument.go
  */
-func (policy IamRolePolicy) NoStatementHasWildcardResource() bool {
-	for _, statement := range *policy.PolicyDocument.Statements {
-		if statement.isResourceAWildcard() {
-			return false
-		}
-	}
-	return true
+func (policy IamRolePolicy) StatementsWithResourceBroaderThan(limit ResourceBreadth) []StatementFinding {
+	return policy.PolicyDocument.StatementsWithResourceBroaderThan(limit)
+}
+
+/**
+ * Returns whether no "Allow" statement in the policy has an action that is a wildcard ('*').
+ *
+ * see (pd *PolicyDocument)NoStatementHasWildcardAction() bool in policydocument.go
+ */
+func (policy IamRolePolicy) NoStatementHasWildcardAction() bool {
+	return policy.PolicyDocument.NoStatementHasWildcardAction()
+}
+
+/**
+ * Returns a finding for every Action value of an "Allow" statement that is a wildcard ('*').
+ *
+ * see (pd *PolicyDocument)StatementsWithWildcardAction() []StatementFinding in policydocument.go
+ */
+func (policy IamRolePolicy) StatementsWithWildcardAction() []StatementFinding {
+	return policy.PolicyDocument.StatementsWithWildcardAction()
+}
+
+/**
+ * Returns a finding for every Action value of an "Allow" statement that covers a whole service (e.g. "iam:*").
+ *
+ * see (pd *PolicyDocument)StatementsWithServiceWideAction() []StatementFinding in policydocument.go
+ */
+func (policy IamRolePolicy) StatementsWithServiceWideAction() []StatementFinding {
+	return policy.PolicyDocument.StatementsWithServiceWideAction()
+}
+
+/**
+ * Evaluates the policy document of the policy for a request.
+ *
+ * see (pd *PolicyDocument)IsAllowed(principal, action, resource string, context RequestContext) EvaluationResult in evaluation.go
+ */
+func (policy IamRolePolicy) IsAllowed(principal string, action string, resource string, context RequestContext) EvaluationResult {
+	return policy.PolicyDocument.IsAllowed(principal, action, resource, context)
 }
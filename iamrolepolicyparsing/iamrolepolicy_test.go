@@ -3,6 +3,8 @@ package iamrolepolicyparsing
 import (
 	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 )
@@ -166,7 +168,7 @@ func TestIamRolePolicy_UnmarshalWhenNull(t *testing.T) {
 }
 
 func TestIamRolePolicy_Unmarshal(t *testing.T) {
-	data := `{"PolicyName": "policyName", "PolicyDocument":{"Version": "2008-10-17", "Id": "i2d", "Statement":[{"Effect":"Allow","NotPrincipal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"NotAction":"s3:ListBucket","Resource":["arn:aws:s3:::example-bucket"]}]}}`
+	data := `{"PolicyName": "policyName", "PolicyDocument":{"Version": "2008-10-17", "Id": "i2d", "Statement":[{"Effect":"Allow","NotAction":"s3:ListBucket","Resource":["arn:aws:s3:::example-bucket"]}]}}`
 	var iamRolePolicy IamRolePolicy
 	err := json.Unmarshal([]byte(data), &iamRolePolicy)
 	// todo compare against wanted
@@ -177,10 +179,10 @@ func TestIamRolePolicy_Unmarshal(t *testing.T) {
 }
 
 func TestIamRolePolicy_UnmarshalWhenMissingPolicyName(t *testing.T) {
-	data := `{"PolicyDocument":{"Version": "2008-10-17", "Id": "i2d", "Statement":[{"Effect":"Allow","NotPrincipal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"NotAction":"s3:ListBucket","Resource":["arn:aws:s3:::example-bucket"]}]}}`
+	data := `{"PolicyDocument":{"Version": "2008-10-17", "Id": "i2d", "Statement":[{"Effect":"Allow","NotAction":"s3:ListBucket","Resource":["arn:aws:s3:::example-bucket"]}]}}`
 	var iamRolePolicy IamRolePolicy
 	err := json.Unmarshal([]byte(data), &iamRolePolicy)
-	expectedErr := errors.New("PolicyName is required")
+	expectedErr := &ParseError{Code: CodeMissingKey, Path: "/PolicyName", Message: "PolicyName is required", Line: 1, Column: 1}
 
 	if !reflect.DeepEqual(err, expectedErr) {
 		t.Errorf("Expected error: %s, got: %s", expectedErr.Error(), err.Error())
@@ -192,7 +194,7 @@ func TestIamRolePolicy_UnmarshalWhenMissingPolicyDocument(t *testing.T) {
 	data := `{"PolicyName": "policyName"}`
 	var iamRolePolicy IamRolePolicy
 	err := json.Unmarshal([]byte(data), &iamRolePolicy)
-	expectedErr := errors.New("PolicyDocument is required")
+	expectedErr := &ParseError{Code: CodeMissingKey, Path: "/PolicyDocument", Message: "PolicyDocument is required", Line: 1, Column: 1}
 
 	if !reflect.DeepEqual(err, expectedErr) {
 		t.Errorf("Expected error: %s, got: %s", expectedErr.Error(), err.Error())
@@ -200,16 +202,40 @@ func TestIamRolePolicy_UnmarshalWhenMissingPolicyDocument(t *testing.T) {
 }
 
 func TestIamRolePolicy_UnmarshalWhenMissingPolicyNameIsArray(t *testing.T) {
-	data := `{"PolicyName": [1,2], "PolicyDocument":{"Version": "2008-10-17", "Id": "i2d", "Statement":[{"Effect":"Allow","NotPrincipal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"NotAction":"s3:ListBucket","Resource":["arn:aws:s3:::example-bucket"]}]}}`
+	data := `{"PolicyName": [1,2], "PolicyDocument":{"Version": "2008-10-17", "Id": "i2d", "Statement":[{"Effect":"Allow","NotAction":"s3:ListBucket","Resource":["arn:aws:s3:::example-bucket"]}]}}`
 	var iamRolePolicy IamRolePolicy
 	err := json.Unmarshal([]byte(data), &iamRolePolicy)
-	expectedErr := errors.New("error unmarshalling a policy: json: cannot unmarshal array into Go struct field .PolicyName of type string")
+	expectedErr := &ParseError{Code: CodeInvalidType, Path: "/PolicyName", Message: "PolicyName should be a string", Line: 1, Column: 2}
 
 	if !reflect.DeepEqual(err, expectedErr) {
 		t.Errorf("Expected error: %s, got: %s", expectedErr.Error(), err.Error())
 	}
 }
 
+func TestIamRolePolicy_UnmarshalWithRoleName(t *testing.T) {
+	data := `{"PolicyName": "policyName", "RoleName": "roleName", "PolicyDocument":{"Version": "2012-10-17", "Statement":[{"Effect":"Allow","Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}]}}`
+	var iamRolePolicy IamRolePolicy
+	err := json.Unmarshal([]byte(data), &iamRolePolicy)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err.Error())
+	}
+	if iamRolePolicy.RoleName == nil || *iamRolePolicy.RoleName != "roleName" {
+		t.Errorf("Expected: roleName, got: %v", iamRolePolicy.RoleName)
+	}
+}
+
+func TestIamRolePolicy_UnmarshalWhenRoleNameIsArray(t *testing.T) {
+	data := `{"PolicyName": "policyName", "RoleName": ["roleName"], "PolicyDocument":{"Version": "2012-10-17", "Statement":[]}}`
+	var iamRolePolicy IamRolePolicy
+	err := json.Unmarshal([]byte(data), &iamRolePolicy)
+	expectedErr := &ParseError{Code: CodeInvalidType, Path: "/RoleName", Message: "RoleName should be a string", Line: 1, Column: 30}
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+	}
+}
+
 func TestIamRolePolicy_UnmarshalWithInvalidJSON(t *testing.T) {
 	data := `1nval1d`
 	var iamRolePolicy IamRolePolicy
@@ -225,7 +251,7 @@ func TestIamRolePolicy_UnmarshalWhenCouldNotParsePolicyDocument(t *testing.T) {
 	data := `{"PolicyName": 123, "PolicyDocument":{"Version": "2004-10-17", "Id": "i2d", "Statement":[{"Effect":"Allow","NotPrincipal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"NotAction":"s3:ListBucket","Resource":["arn:aws:s3:::example-bucket"]}]}}`
 	var iamRolePolicy IamRolePolicy
 	err := json.Unmarshal([]byte(data), &iamRolePolicy)
-	expectedErr := errors.New("error unmarshalling a policy: Version must be 2012-10-17 or 2008-10-17")
+	expectedErr := &ParseError{Code: CodeInvalidVersion, Path: "/PolicyDocument/Version", Message: "Version must be 2012-10-17 or 2008-10-17", Line: 1, Column: 39}
 
 	if !reflect.DeepEqual(err, expectedErr) {
 		t.Errorf("Expected error: %s, got: %s", expectedErr.Error(), err.Error())
@@ -285,3 +311,326 @@ func TestIamRolePolicy_NoStatementHasWildcardResourceWhenTrue(t *testing.T) {
 		t.Errorf("Expected true, got false")
 	}
 }
+
+func TestIamRolePolicy_MarshalJSON(t *testing.T) {
+	policy := IamRolePolicy{
+		PolicyName: stringOf("policyName"),
+		PolicyDocument: &PolicyDocument{
+			Version:    stringOf("2012-10-17"),
+			Statements: &[]Statement{},
+		},
+	}
+	expected := `{"PolicyName":"policyName","PolicyDocument":{"Version":"2012-10-17","Statement":[]}}`
+
+	data, err := json.Marshal(policy)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if string(data) != expected {
+		t.Errorf("Expected: %s, got: %s", expected, string(data))
+	}
+}
+
+func TestIamRolePolicy_MarshalJSONWithRoleName(t *testing.T) {
+	policy := IamRolePolicy{
+		PolicyName: stringOf("policyName"),
+		RoleName:   stringOf("roleName"),
+		PolicyDocument: &PolicyDocument{
+			Version:    stringOf("2012-10-17"),
+			Statements: &[]Statement{},
+		},
+	}
+	expected := `{"PolicyName":"policyName","RoleName":"roleName","PolicyDocument":{"Version":"2012-10-17","Statement":[]}}`
+
+	data, err := json.Marshal(policy)
+
+	if err != nil || string(data) != expected {
+		t.Errorf("Expected: %s, got: %s (error: %v)", expected, string(data), err)
+	}
+}
+
+func TestIamRolePolicy_MarshalJSONRoundTripOverExampleJsons(t *testing.T) {
+	files, err := filepath.Glob("../example-jsons/*")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Expected example JSON files, got: %v (error: %v)", files, err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("Error reading file: %v", err)
+			}
+			var policy IamRolePolicy
+			if err := json.Unmarshal(data, &policy); err != nil {
+				t.Skipf("not a valid policy: %v", err)
+			}
+
+			marshalled, err := json.Marshal(policy)
+			if err != nil {
+				t.Fatalf("Expected error: <nil>, got: %v", err)
+			}
+
+			var original, roundTripped interface{}
+			if err := json.Unmarshal(data, &original); err != nil {
+				t.Fatalf("Expected error: <nil>, got: %v", err)
+			}
+			if err := json.Unmarshal(marshalled, &roundTripped); err != nil {
+				t.Fatalf("Expected error: <nil>, got: %v", err)
+			}
+			if !reflect.DeepEqual(original, roundTripped) {
+				t.Errorf("Expected: \n%v\n\t, got: \n%v", original, roundTripped)
+			}
+
+			var reparsed IamRolePolicy
+			if err := json.Unmarshal(marshalled, &reparsed); err != nil {
+				t.Fatalf("Expected error: <nil>, got: %v", err)
+			}
+			if !policy.Equals(reparsed) {
+				t.Errorf("Expected: \n%v\n\t, got: \n%v", policy.String(), reparsed.String())
+			}
+		})
+	}
+}
+
+func TestIamRolePolicy_NoStatementHasWildcardResourceWhenInArray(t *testing.T) {
+	data := `{"PolicyName": "policyName", "PolicyDocument":{"Version": "2012-10-17", "Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":["arn:aws:s3:::bucket/*", "*"]}]}}`
+	var policy IamRolePolicy
+	if err := json.Unmarshal([]byte(data), &policy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+
+	if policy.NoStatementHasWildcardResource() {
+		t.Errorf("Expected false, got true")
+	}
+}
+
+func TestIamRolePolicy_StatementsWithWildcardResource(t *testing.T) {
+	data := `{"PolicyName": "policyName", "PolicyDocument":{"Version": "2012-10-17", "Statement":[
+		{"Sid":"First","Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::bucket/*"},
+		{"Sid":"Second","Effect":"Allow","Action":"s3:GetObject","Resource":["arn:aws:s3:::bucket/*", "*"]},
+		{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"},
+		{"Effect":"Allow","Action":"s3:GetObject","NotResource":"*"}
+	]}}`
+	var policy IamRolePolicy
+	if err := json.Unmarshal([]byte(data), &policy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+	expected := []StatementFinding{
+		{StatementIndex: 1, Sid: stringOf("Second"), ElementIndex: 1, Value: "*"},
+		{StatementIndex: 2, Sid: nil, ElementIndex: 0, Value: "*"},
+	}
+
+	findings := policy.StatementsWithWildcardResource()
+
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, findings)
+	}
+}
+
+func TestIamRolePolicy_StatementsWithResourceBroaderThan(t *testing.T) {
+	data := `{"PolicyName": "policyName", "PolicyDocument":{"Version": "2012-10-17", "Statement":[
+		{"Sid":"Narrow","Effect":"Allow","Action":"s3:GetObject","Resource":["arn:aws:s3:::bucket/*", "arn:aws:s3:::*"]},
+		{"Sid":"Roles","Effect":"Allow","Action":"iam:PassRole","Resource":"arn:aws:iam::123456789012:role/*"},
+		{"Sid":"Deny","Effect":"Deny","Action":"s3:*","Resource":"*"},
+		{"Sid":"Not","Effect":"Allow","Action":"s3:GetObject","NotResource":"arn:aws:s3:::secret/*"}
+	]}}`
+	var policy IamRolePolicy
+	if err := json.Unmarshal([]byte(data), &policy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+	expected := []StatementFinding{
+		{StatementIndex: 0, Sid: stringOf("Narrow"), ElementIndex: 1, Value: "arn:aws:s3:::*"},
+		{StatementIndex: 3, Sid: stringOf("Not"), ElementIndex: -1, Value: "NotResource"},
+	}
+
+	findings := policy.StatementsWithResourceBroaderThan(BreadthAccountWide)
+
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, findings)
+	}
+}
+
+func TestIamRolePolicy_StatementsWithResourceBroaderThanGlobal(t *testing.T) {
+	data := `{"PolicyName": "policyName", "PolicyDocument":{"Version": "2012-10-17", "Statement":[
+		{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"},
+		{"Effect":"Allow","Action":"s3:GetObject","NotResource":"arn:aws:s3:::secret/*"}
+	]}}`
+	var policy IamRolePolicy
+	if err := json.Unmarshal([]byte(data), &policy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+
+	if findings := policy.StatementsWithResourceBroaderThan(BreadthGlobal); findings != nil {
+		t.Errorf("Expected: <nil>, got: %v", findings)
+	}
+}
+
+func TestIamRolePolicy_StatementsWithWildcardAction(t *testing.T) {
+	data := `{"PolicyName": "policyName", "PolicyDocument":{"Version": "2012-10-17", "Statement":[
+		{"Sid":"Everything","Effect":"Allow","Action":["s3:GetObject","*"],"Resource":"arn:aws:s3:::bucket/key"},
+		{"Sid":"Iam","Effect":"Allow","Action":"iam:*","Resource":"arn:aws:iam::123456789012:role/Admin"},
+		{"Sid":"Deny","Effect":"Deny","Action":"*","Resource":"*"},
+		{"Sid":"Not","Effect":"Allow","NotAction":"*","Resource":"*"}
+	]}}`
+	var policy IamRolePolicy
+	if err := json.Unmarshal([]byte(data), &policy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+	expected := []StatementFinding{
+		{StatementIndex: 0, Sid: stringOf("Everything"), ElementIndex: 1, Value: "*"},
+	}
+
+	findings := policy.StatementsWithWildcardAction()
+
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, findings)
+	}
+	if policy.NoStatementHasWildcardAction() {
+		t.Errorf("Expected false, got true")
+	}
+}
+
+func TestIamRolePolicy_StatementsWithServiceWideAction(t *testing.T) {
+	data := `{"PolicyName": "policyName", "PolicyDocument":{"Version": "2012-10-17", "Statement":[
+		{"Sid":"Everything","Effect":"Allow","Action":["s3:GetObject","*"],"Resource":"arn:aws:s3:::bucket/key"},
+		{"Sid":"Iam","Effect":"Allow","Action":"iam:*","Resource":"arn:aws:iam::123456789012:role/Admin"},
+		{"Sid":"Deny","Effect":"Deny","Action":"s3:*","Resource":"*"}
+	]}}`
+	var policy IamRolePolicy
+	if err := json.Unmarshal([]byte(data), &policy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+	expected := []StatementFinding{
+		{StatementIndex: 1, Sid: stringOf("Iam"), ElementIndex: 0, Value: "iam:*"},
+	}
+
+	findings := policy.StatementsWithServiceWideAction()
+
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, findings)
+	}
+}
+
+func TestIamRolePolicy_NoStatementHasWildcardActionWhenTrue(t *testing.T) {
+	data := `{"PolicyName": "policyName", "PolicyDocument":{"Version": "2012-10-17", "Statement":[
+		{"Effect":"Allow","Action":["s3:GetObject","s3:List*"],"Resource":"*"}
+	]}}`
+	var policy IamRolePolicy
+	if err := json.Unmarshal([]byte(data), &policy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+
+	if !policy.NoStatementHasWildcardAction() {
+		t.Errorf("Expected true, got false")
+	}
+}
+
+func TestIamRolePolicy_StatementsWithWildcardActionAfterAnInvalidAction(t *testing.T) {
+	data := `{"PolicyName": "policyName", "PolicyDocument":{"Version": "2012-10-17", "Statement":[
+		{"Effect":"Allow","Action":["bad","*","iam:*"],"Resource":"*"}
+	]}}`
+	var policy IamRolePolicy
+	if parseErrors := policy.Validate([]byte(data)); len(parseErrors) != 1 {
+		t.Fatalf("Expected 1 error, got: %v", parseErrors)
+	}
+	expectedWildcards := []StatementFinding{{StatementIndex: 0, ElementIndex: 1, Value: "*"}}
+	expectedServiceWide := []StatementFinding{{StatementIndex: 0, ElementIndex: 2, Value: "iam:*"}}
+
+	if findings := policy.StatementsWithWildcardAction(); !reflect.DeepEqual(findings, expectedWildcards) {
+		t.Errorf("Expected: %v, got: %v", expectedWildcards, findings)
+	}
+	if findings := policy.StatementsWithServiceWideAction(); !reflect.DeepEqual(findings, expectedServiceWide) {
+		t.Errorf("Expected: %v, got: %v", expectedServiceWide, findings)
+	}
+}
+
+func TestIamRolePolicy_NoStatementHasWildcardActionWhenFalse(t *testing.T) {
+	stringOf := func(s string) *string { return &s }
+	policy := IamRolePolicy{
+		PolicyName: stringOf("123"),
+		PolicyDocument: &PolicyDocument{
+			Version: stringOf("1"),
+			Id:      stringOf("2"),
+			Statements: &[]Statement{
+				{
+					Sid:           stringOf("123"),
+					Effect:        stringOf("Allow"),
+					Action:        true,
+					Resource:      true,
+					ActionValue:   []interface{}{"*", "iam:*"},
+					ResourceValue: interface{}("arn:aws:s3:::bucket"),
+				},
+			},
+		},
+	}
+	expectedServiceWide := []StatementFinding{{StatementIndex: 0, Sid: stringOf("123"), ElementIndex: 1, Value: "iam:*"}}
+
+	if policy.NoStatementHasWildcardAction() {
+		t.Errorf("Expected false, got true")
+	}
+	if findings := policy.StatementsWithServiceWideAction(); !reflect.DeepEqual(findings, expectedServiceWide) {
+		t.Errorf("Expected: %v, got: %v", expectedServiceWide, findings)
+	}
+}
+
+func TestIamRolePolicy_ValidateCollectsAllErrors(t *testing.T) {
+	data := `{"PolicyName": 1, "Extra": true, "PolicyDocument": {"Version": "2012-10-17", "Statement": [
+		{"Effect": "Maybe", "Action": ["s3:GetObject", "s3GetObject"], "Resource": ["arn:aws:s3:bucket", "*"]},
+		{"Effect": "Allow", "Action": "s3:*"}
+	]}}`
+	var iamRolePolicy IamRolePolicy
+	expected := []string{
+		`/Extra: unknown key: Extra`,
+		`/PolicyDocument/Statement/0/Effect: effect should be either "Allow" or "Deny"`,
+		`/PolicyDocument/Statement/0/Action/1: action "s3GetObject" should have the form service:action, it has no colon`,
+		`/PolicyDocument/Statement/0/Resource/0: ARN "arn:aws:s3:bucket" should have the form arn:partition:service:region:account:resource`,
+		`/PolicyDocument/Statement/1/Resource: resource or not-resource has to exist in a statement block`,
+		`/PolicyName: PolicyName should be a string`,
+	}
+
+	parseErrors := iamRolePolicy.Validate([]byte(data))
+
+	var messages []string
+	for _, parseError := range parseErrors {
+		messages = append(messages, parseError.Error())
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected: %q, got: %q", expected, messages)
+	}
+	if len(iamRolePolicy.PolicyDocument.StatementList()) != 2 || len(iamRolePolicy.PolicyDocument.StatementList()[0].Actions) != 1 {
+		t.Errorf("Expected the valid parts of the statements to be parsed, got: %v", iamRolePolicy.PolicyDocument)
+	}
+}
+
+func TestIamRolePolicy_ValidateWhenValid(t *testing.T) {
+	data := `{"PolicyName": "name", "PolicyDocument": {"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}}}`
+	var iamRolePolicy IamRolePolicy
+
+	if parseErrors := iamRolePolicy.Validate([]byte(data)); parseErrors != nil {
+		t.Errorf("Expected: <nil>, got: %v", parseErrors)
+	}
+	if iamRolePolicy.PolicyName == nil || *iamRolePolicy.PolicyName != "name" {
+		t.Errorf("Expected the policy to be parsed, got: %v", iamRolePolicy)
+	}
+}
+
+func TestIamRolePolicy_UnmarshalReturnsTheFirstError(t *testing.T) {
+	data := `{"PolicyName": "name", "PolicyDocument": {"Statement": [{"Effect": "Allow", "Action": "s3GetObject"}]}}`
+	var iamRolePolicy IamRolePolicy
+	expectedErr := &ParseError{
+		Code:    CodeInvalidAction,
+		Path:    "/PolicyDocument/Statement/0/Action",
+		Message: `action "s3GetObject" should have the form service:action, it has no colon`,
+		Err:     errors.New(`action "s3GetObject" should have the form service:action, it has no colon`),
+		Line:    1,
+		Column:  77,
+	}
+
+	err := iamRolePolicy.UnmarshalJSON([]byte(data))
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+	}
+}
@@ -0,0 +1,260 @@
+package iamrolepolicyparsing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+/**
+ * IamManagedPolicy struct represents the properties of an AWS::IAM::ManagedPolicy.
+ * Only PolicyDocument is required, the policy can be attached to groups, roles and users later on.
+ *
+ * A managed policy is a standalone identity policy, its statements can't name a principal.
+ *
+ * for grammar see https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/aws-resource-iam-managedpolicy.html
+ */
+type IamManagedPolicy struct {
+	ManagedPolicyName *string         `json:"ManagedPolicyName"`
+	Description       *string         `json:"Description"`
+	Path              *string         `json:"Path"`
+	Groups            []string        `json:"Groups"`
+	Roles             []string        `json:"Roles"`
+	Users             []string        `json:"Users"`
+	PolicyDocument    *PolicyDocument `json:"PolicyDocument"`
+}
+
+func (this IamManagedPolicy) String() string {
+	return fmt.Sprintf(
+		"IamManagedPolicy{ManagedPolicyName: %s, Description: %s, Path: %s, Groups: %v, Roles: %v, Users: %v, PolicyDocument: %s}",
+		stringPtrString(this.ManagedPolicyName),
+		stringPtrString(this.Description),
+		stringPtrString(this.Path),
+		this.Groups,
+		this.Roles,
+		this.Users,
+		this.PolicyDocument.String(),
+	)
+}
+
+/**
+ * Parses the properties of an AWS::IAM::ManagedPolicy.
+ * Only the first error is returned, see Validate for getting all of them.
+ */
+func (policy *IamManagedPolicy) UnmarshalJSON(data []byte) error {
+	return firstError(checkSource(data, policy.unmarshal(data)))
+}
+
+/**
+ * Returns every error of the managed policy instead of only the first one. As only PolicyDocument is required,
+ * these are mostly errors of the document. Returns nil if the policy is valid.
+ */
+func (policy *IamManagedPolicy) Validate(data []byte) []*ParseError {
+	return toParseErrors(checkSource(data, policy.unmarshal(data)))
+}
+
+func (policy *IamManagedPolicy) unmarshal(data []byte) []error {
+	return unmarshalEnvelope(data, &policy.PolicyDocument, PolicyKindIdentity, nil,
+		envelopeField{key: "ManagedPolicyName", target: &policy.ManagedPolicyName},
+		envelopeField{key: "Description", target: &policy.Description},
+		envelopeField{key: "Path", target: &policy.Path},
+		envelopeField{key: "Groups", target: &policy.Groups},
+		envelopeField{key: "Roles", target: &policy.Roles},
+		envelopeField{key: "Users", target: &policy.Users},
+	)
+}
+
+/**
+ * Writes the policy back as the properties of an AWS::IAM::ManagedPolicy.
+ * Absent values are omitted.
+ */
+func (policy IamManagedPolicy) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		ManagedPolicyName *string         `json:"ManagedPolicyName,omitempty"`
+		Description       *string         `json:"Description,omitempty"`
+		Path              *string         `json:"Path,omitempty"`
+		Groups            []string        `json:"Groups,omitempty"`
+		Roles             []string        `json:"Roles,omitempty"`
+		Users             []string        `json:"Users,omitempty"`
+		PolicyDocument    *PolicyDocument `json:"PolicyDocument,omitempty"`
+	}(policy)
+	return json.Marshal(aux)
+}
+
+/**
+ * IamPolicy struct represents the properties of an AWS::IAM::Policy, an inline policy embedded in the listed
+ * groups, roles and users, at least one of which is required.
+ *
+ * Principal and NotPrincipal are errors, the principals being the groups, roles and users the policy is embedded in.
+ *
+ * for grammar see https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/aws-resource-iam-policy.html
+ */
+type IamPolicy struct {
+	PolicyName     *string         `json:"PolicyName"`
+	Groups         []string        `json:"Groups"`
+	Roles          []string        `json:"Roles"`
+	Users          []string        `json:"Users"`
+	PolicyDocument *PolicyDocument `json:"PolicyDocument"`
+}
+
+func (this IamPolicy) String() string {
+	return fmt.Sprintf(
+		"IamPolicy{PolicyName: %s, Groups: %v, Roles: %v, Users: %v, PolicyDocument: %s}",
+		stringPtrString(this.PolicyName),
+		this.Groups,
+		this.Roles,
+		this.Users,
+		this.PolicyDocument.String(),
+	)
+}
+
+/**
+ * Parses the properties of an AWS::IAM::Policy.
+ * Only the first error is returned, see Validate for getting all of them.
+ */
+func (policy *IamPolicy) UnmarshalJSON(data []byte) error {
+	return firstError(checkSource(data, policy.unmarshal(data)))
+}
+
+/**
+ * Returns every error of the inline policy instead of only the first one, including a missing PolicyName and
+ * a policy embedded in no group, role or user (reported at the path ""). Returns nil if the policy is valid.
+ */
+func (policy *IamPolicy) Validate(data []byte) []*ParseError {
+	return toParseErrors(checkSource(data, policy.unmarshal(data)))
+}
+
+func (policy *IamPolicy) unmarshal(data []byte) []error {
+	errs := unmarshalEnvelope(data, &policy.PolicyDocument, PolicyKindIdentity, nil,
+		envelopeField{key: "PolicyName", target: &policy.PolicyName, required: true},
+		envelopeField{key: "Groups", target: &policy.Groups},
+		envelopeField{key: "Roles", target: &policy.Roles},
+		envelopeField{key: "Users", target: &policy.Users},
+	)
+	isObject := bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+	if isObject && len(policy.Groups)+len(policy.Roles)+len(policy.Users) == 0 {
+		errs = append(errs, newParseError(CodeMissingKey, "", "at least one of Groups, Roles and Users is required"))
+	}
+	return errs
+}
+
+/**
+ * Writes the policy back as the properties of an AWS::IAM::Policy.
+ * Absent values are omitted.
+ */
+func (policy IamPolicy) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		PolicyName     *string         `json:"PolicyName,omitempty"`
+		Groups         []string        `json:"Groups,omitempty"`
+		Roles          []string        `json:"Roles,omitempty"`
+		Users          []string        `json:"Users,omitempty"`
+		PolicyDocument *PolicyDocument `json:"PolicyDocument,omitempty"`
+	}(policy)
+	return json.Marshal(aux)
+}
+
+/**
+ * IamUserPolicy struct represents the properties of an AWS::IAM::UserPolicy, an inline policy of a user.
+ *
+ * The user is the principal of the policy, so its statements can't have a Principal or NotPrincipal.
+ *
+ * for grammar see https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/aws-resource-iam-userpolicy.html
+ */
+type IamUserPolicy struct {
+	PolicyName     *string         `json:"PolicyName"`
+	UserName       *string         `json:"UserName"`
+	PolicyDocument *PolicyDocument `json:"PolicyDocument"`
+}
+
+func (this IamUserPolicy) String() string {
+	return fmt.Sprintf("IamUserPolicy{PolicyName: %s, UserName: %s, PolicyDocument: %s}", stringPtrString(this.PolicyName), stringPtrString(this.UserName), this.PolicyDocument.String())
+}
+
+/**
+ * Parses the properties of an AWS::IAM::UserPolicy.
+ * Only the first error is returned, see Validate for getting all of them.
+ */
+func (policy *IamUserPolicy) UnmarshalJSON(data []byte) error {
+	return firstError(checkSource(data, policy.unmarshal(data)))
+}
+
+/**
+ * Returns every error of the user policy instead of only the first one, e.g. a missing UserName along with
+ * the errors of its document. Returns nil if the policy is valid.
+ */
+func (policy *IamUserPolicy) Validate(data []byte) []*ParseError {
+	return toParseErrors(checkSource(data, policy.unmarshal(data)))
+}
+
+func (policy *IamUserPolicy) unmarshal(data []byte) []error {
+	return unmarshalEnvelope(data, &policy.PolicyDocument, PolicyKindIdentity, nil,
+		envelopeField{key: "PolicyName", target: &policy.PolicyName, required: true},
+		envelopeField{key: "UserName", target: &policy.UserName, required: true},
+	)
+}
+
+/**
+ * Writes the policy back as the properties of an AWS::IAM::UserPolicy.
+ * Absent values are omitted.
+ */
+func (policy IamUserPolicy) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		PolicyName     *string         `json:"PolicyName,omitempty"`
+		UserName       *string         `json:"UserName,omitempty"`
+		PolicyDocument *PolicyDocument `json:"PolicyDocument,omitempty"`
+	}(policy)
+	return json.Marshal(aux)
+}
+
+/**
+ * IamGroupPolicy struct represents the properties of an AWS::IAM::GroupPolicy, an inline policy of a group.
+ *
+ * The policy applies to every member of the group, which can't be narrowed down with a Principal or NotPrincipal.
+ *
+ * for grammar see https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/aws-resource-iam-grouppolicy.html
+ */
+type IamGroupPolicy struct {
+	PolicyName     *string         `json:"PolicyName"`
+	GroupName      *string         `json:"GroupName"`
+	PolicyDocument *PolicyDocument `json:"PolicyDocument"`
+}
+
+func (this IamGroupPolicy) String() string {
+	return fmt.Sprintf("IamGroupPolicy{PolicyName: %s, GroupName: %s, PolicyDocument: %s}", stringPtrString(this.PolicyName), stringPtrString(this.GroupName), this.PolicyDocument.String())
+}
+
+/**
+ * Parses the properties of an AWS::IAM::GroupPolicy.
+ * Only the first error is returned, see Validate for getting all of them.
+ */
+func (policy *IamGroupPolicy) UnmarshalJSON(data []byte) error {
+	return firstError(checkSource(data, policy.unmarshal(data)))
+}
+
+/**
+ * Returns every error of the group policy instead of only the first one; a missing GroupName comes after
+ * the errors of the document, as for IamRolePolicy. Returns nil if the policy is valid.
+ */
+func (policy *IamGroupPolicy) Validate(data []byte) []*ParseError {
+	return toParseErrors(checkSource(data, policy.unmarshal(data)))
+}
+
+func (policy *IamGroupPolicy) unmarshal(data []byte) []error {
+	return unmarshalEnvelope(data, &policy.PolicyDocument, PolicyKindIdentity, nil,
+		envelopeField{key: "PolicyName", target: &policy.PolicyName, required: true},
+		envelopeField{key: "GroupName", target: &policy.GroupName, required: true},
+	)
+}
+
+/**
+ * Writes the policy back as the properties of an AWS::IAM::GroupPolicy.
+ * Absent values are omitted.
+ */
+func (policy IamGroupPolicy) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		PolicyName     *string         `json:"PolicyName,omitempty"`
+		GroupName      *string         `json:"GroupName,omitempty"`
+		PolicyDocument *PolicyDocument `json:"PolicyDocument,omitempty"`
+	}(policy)
+	return json.Marshal(aux)
+}
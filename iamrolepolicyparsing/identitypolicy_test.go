@@ -0,0 +1,111 @@
+package iamrolepolicyparsing
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const identityPolicyDocument = `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": ["s3:GetObject", "iam:*"], "Resource": "*"}]}`
+
+func TestIamManagedPolicy_Unmarshal(t *testing.T) {
+	data := `{"ManagedPolicyName": "ReadOnly", "Description": "read only", "Roles": ["Admin"], "PolicyDocument": ` + identityPolicyDocument + `}`
+	var policy IamManagedPolicy
+
+	err := json.Unmarshal([]byte(data), &policy)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if *policy.ManagedPolicyName != "ReadOnly" || !reflect.DeepEqual(policy.Roles, []string{"Admin"}) || policy.Users != nil {
+		t.Errorf("Expected the policy to be parsed, got: %v", policy)
+	}
+	expected := []StatementFinding{{StatementIndex: 0, ElementIndex: 1, Value: "iam:*"}}
+	if findings := policy.PolicyDocument.StatementsWithServiceWideAction(); !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, findings)
+	}
+}
+
+func TestIamManagedPolicy_Validate(t *testing.T) {
+	data := `{"Roles": "Admin", "PolicyName": "name", "PolicyDocument": {"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "*"}
+	]}}`
+	var policy IamManagedPolicy
+	expected := []string{
+		"/PolicyName: unknown key: PolicyName",
+		"/PolicyDocument/Statement/0/Principal: Principal is not allowed in an identity policy",
+		"/Roles: Roles should be an array of strings",
+	}
+
+	messages := validationMessages(policy.Validate([]byte(data)))
+
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected: %q, got: %q", expected, messages)
+	}
+}
+
+func TestIamManagedPolicy_MarshalJSON(t *testing.T) {
+	data := `{"ManagedPolicyName":"ReadOnly","Users":["JohnDoe"],"PolicyDocument":{"Version":"2012-10-17","Statement":[]}}`
+	var policy IamManagedPolicy
+	if err := json.Unmarshal([]byte(data), &policy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+
+	marshalled, err := json.Marshal(policy)
+
+	if err != nil || string(marshalled) != data {
+		t.Errorf("Expected: %s, got: %s (error: %v)", data, marshalled, err)
+	}
+}
+
+func TestIamPolicy_Unmarshal(t *testing.T) {
+	data := `{"PolicyName": "name", "Groups": ["Developers"], "PolicyDocument": ` + identityPolicyDocument + `}`
+	var policy IamPolicy
+
+	err := json.Unmarshal([]byte(data), &policy)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if *policy.PolicyName != "name" || !reflect.DeepEqual(policy.Groups, []string{"Developers"}) {
+		t.Errorf("Expected the policy to be parsed, got: %v", policy)
+	}
+}
+
+func TestIamPolicy_ValidateWithoutGroupsRolesOrUsers(t *testing.T) {
+	data := `{"PolicyDocument": ` + identityPolicyDocument + `}`
+	var policy IamPolicy
+	expected := []string{"/PolicyName: PolicyName is required", "at least one of Groups, Roles and Users is required"}
+
+	messages := validationMessages(policy.Validate([]byte(data)))
+
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected: %q, got: %q", expected, messages)
+	}
+}
+
+func TestIamUserPolicy_UnmarshalWhenMissingUserName(t *testing.T) {
+	data := `{"PolicyName": "name", "PolicyDocument": ` + identityPolicyDocument + `}`
+	var policy IamUserPolicy
+	expectedErr := &ParseError{Code: CodeMissingKey, Path: "/UserName", Message: "UserName is required", Line: 1, Column: 1}
+
+	err := json.Unmarshal([]byte(data), &policy)
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %+v, got: %+v", expectedErr, err)
+	}
+}
+
+func TestIamGroupPolicy_Unmarshal(t *testing.T) {
+	data := `{"PolicyName": "name", "GroupName": "Developers", "PolicyDocument": ` + identityPolicyDocument + `}`
+	var policy IamGroupPolicy
+
+	err := json.Unmarshal([]byte(data), &policy)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if *policy.GroupName != "Developers" || policy.PolicyDocument.NoStatementHasWildcardResource() {
+		t.Errorf("Expected the policy to be parsed, got: %v", policy)
+	}
+}
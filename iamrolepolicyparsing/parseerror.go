@@ -0,0 +1,174 @@
+package iamrolepolicyparsing
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/**
+ * ErrorCode classifies a ParseError.
+ */
+type ErrorCode string
+
+const (
+	CodeInvalidJSON               ErrorCode = "invalid-json"
+	CodeInvalidType               ErrorCode = "invalid-type"
+	CodeUnknownKey                ErrorCode = "unknown-key"
+	CodeDuplicateKey              ErrorCode = "duplicate-key"
+	CodeMissingKey                ErrorCode = "missing-key"
+	CodeConflictingKeys           ErrorCode = "conflicting-keys"
+	CodeInvalidVersion            ErrorCode = "invalid-version"
+	CodeInvalidSid                ErrorCode = "invalid-sid"
+	CodeDuplicateSid              ErrorCode = "duplicate-sid"
+	CodeInvalidEffect             ErrorCode = "invalid-effect"
+	CodeInvalidPrincipal          ErrorCode = "invalid-principal"
+	CodePrincipalNotAllowed       ErrorCode = "principal-not-allowed"
+	CodeInvalidAction             ErrorCode = "invalid-action"
+	CodeInvalidResource           ErrorCode = "invalid-resource"
+	CodeInvalidCondition          ErrorCode = "invalid-condition"
+	CodeInvalidPolicyVariable     ErrorCode = "invalid-policy-variable"
+	CodeUnsupportedPolicyVariable ErrorCode = "unsupported-policy-variable"
+)
+
+/**
+ * ParseError is the error returned by the UnmarshalJSON methods of IamRolePolicy, PolicyDocument and Statement.
+ *
+ * Code classifies the error, Path is a JSON pointer (RFC 6901) to the offending value, relative to the value
+ * that was being unmarshalled (e.g. "/PolicyDocument/Statement/3/Principal" for an IamRolePolicy, "" for the value itself),
+ * and Message describes the problem. Err is the underlying error, if any (e.g. the error of ParseARN).
+ * Line and Column are the 1-based position of the offending value (or of its key) in the unmarshalled JSON,
+ * 0 if unknown (see sourceposition.go).
+ *
+ * The sentinel errors below match any ParseError with the same code, so that callers can write
+ * errors.Is(err, ErrUnknownKey), or errors.As(err, &parseError) to get to the path.
+ */
+type ParseError struct {
+	Code    ErrorCode
+	Path    string
+	Message string
+	Err     error
+	Line    int
+	Column  int
+}
+
+var (
+	ErrInvalidJSON               = &ParseError{Code: CodeInvalidJSON}
+	ErrInvalidType               = &ParseError{Code: CodeInvalidType}
+	ErrUnknownKey                = &ParseError{Code: CodeUnknownKey}
+	ErrDuplicateKey              = &ParseError{Code: CodeDuplicateKey}
+	ErrMissingKey                = &ParseError{Code: CodeMissingKey}
+	ErrConflictingKeys           = &ParseError{Code: CodeConflictingKeys}
+	ErrInvalidVersion            = &ParseError{Code: CodeInvalidVersion}
+	ErrInvalidSid                = &ParseError{Code: CodeInvalidSid}
+	ErrDuplicateSid              = &ParseError{Code: CodeDuplicateSid}
+	ErrInvalidEffect             = &ParseError{Code: CodeInvalidEffect}
+	ErrInvalidPrincipal          = &ParseError{Code: CodeInvalidPrincipal}
+	ErrPrincipalNotAllowed       = &ParseError{Code: CodePrincipalNotAllowed}
+	ErrInvalidAction             = &ParseError{Code: CodeInvalidAction}
+	ErrInvalidResource           = &ParseError{Code: CodeInvalidResource}
+	ErrInvalidCondition          = &ParseError{Code: CodeInvalidCondition}
+	ErrInvalidPolicyVariable     = &ParseError{Code: CodeInvalidPolicyVariable}
+	ErrUnsupportedPolicyVariable = &ParseError{Code: CodeUnsupportedPolicyVariable}
+)
+
+/**
+ * Returns the message prefixed with the path, e.g. `/Statement/0/Effect: effect should be either "Allow" or "Deny"`.
+ */
+func (e *ParseError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+/**
+ * Reports whether target is a ParseError with the same code. A target with a path (or a message) only matches
+ * errors with the same path (or message), so that errors.Is(err, &ParseError{Code: CodeMissingKey, Path: "/PolicyName"}) works.
+ */
+func (e *ParseError) Is(target error) bool {
+	t, ok := target.(*ParseError)
+	if !ok {
+		return false
+	}
+	return t.Code == e.Code &&
+		(t.Path == "" || t.Path == e.Path) &&
+		(t.Message == "" || t.Message == e.Message)
+}
+
+func newParseError(code ErrorCode, path string, message string) error {
+	return &ParseError{Code: code, Path: path, Message: message}
+}
+
+// Wraps an error of a lower-level parser (e.g. ParseARN) keeping its message
+func wrapParseError(code ErrorCode, path string, err error) error {
+	return &ParseError{Code: code, Path: path, Message: err.Error(), Err: err}
+}
+
+// Wraps an error of encoding/json
+func jsonParseError(path string, err error) error {
+	if _, ok := err.(*json.UnmarshalTypeError); ok {
+		return wrapParseError(CodeInvalidType, path, err)
+	}
+	return wrapParseError(CodeInvalidJSON, path, err)
+}
+
+// Returns the error with the prefix prepended to its path, e.g. to turn "/Effect" into "/Statement/3/Effect"
+func prefixErrorPath(prefix string, err error) error {
+	parseError, ok := err.(*ParseError)
+	if !ok {
+		return jsonParseError(prefix, err)
+	}
+	prefixed := *parseError
+	prefixed.Path = prefix + prefixed.Path
+	return &prefixed
+}
+
+// Appends reference tokens (object keys or array indexes) to a JSON pointer, escaping '~' and '/'
+func jsonPointer(path string, tokens ...interface{}) string {
+	var builder strings.Builder
+	builder.WriteString(path)
+	for _, token := range tokens {
+		builder.WriteByte('/')
+		switch token := token.(type) {
+		case int:
+			builder.WriteString(strconv.Itoa(token))
+		default:
+			builder.WriteString(jsonPointerEscaper.Replace(fmt.Sprint(token)))
+		}
+	}
+	return builder.String()
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+var jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+// Returns the first of the errors, or nil
+func firstError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs[0]
+}
+
+// Prefixes the path of every error, see prefixErrorPath
+func prefixErrorPaths(prefix string, errs []error) []error {
+	for i, err := range errs {
+		errs[i] = prefixErrorPath(prefix, err)
+	}
+	return errs
+}
+
+// Returns the errors as *ParseError values for the Validate methods, nil if there are none
+func toParseErrors(errs []error) []*ParseError {
+	var parseErrors []*ParseError
+	for _, err := range errs {
+		parseErrors = append(parseErrors, prefixErrorPath("", err).(*ParseError))
+	}
+	return parseErrors
+}
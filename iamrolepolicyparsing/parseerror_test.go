@@ -0,0 +1,75 @@
+package iamrolepolicyparsing
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseError_PathThroughIamRolePolicy(t *testing.T) {
+	data := `{"PolicyName":"name","PolicyDocument":{"Version":"2012-10-17","Statement":[
+		{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"},
+		{"Effect":"Allow","Principal":"me","Action":"s3:GetObject","Resource":"*"}
+	]}}`
+	var policy IamRolePolicy
+	err := json.Unmarshal([]byte(data), &policy)
+
+	var parseError *ParseError
+	if !errors.As(err, &parseError) {
+		t.Fatalf("Expected a *ParseError, got: %v", err)
+	}
+	if parseError.Code != CodeInvalidPrincipal || parseError.Path != "/PolicyDocument/Statement/1/Principal" {
+		t.Errorf("Expected: %s at /PolicyDocument/Statement/1/Principal, got: %s at %s", CodeInvalidPrincipal, parseError.Code, parseError.Path)
+	}
+	expected := "/PolicyDocument/Statement/1/Principal: principal value should be '*' or a map"
+	if err.Error() != expected {
+		t.Errorf("Expected: %s, got: %s", expected, err.Error())
+	}
+}
+
+func TestParseError_Is(t *testing.T) {
+	var stat Statement
+	err := stat.UnmarshalJSON([]byte(`{"Effect":"Allow","Action":"s3:GetObject","Resource":"*","Extra":1}`))
+
+	if !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Expected errors.Is(err, ErrUnknownKey), got: %v", err)
+	}
+	if errors.Is(err, ErrInvalidEffect) {
+		t.Errorf("Expected !errors.Is(err, ErrInvalidEffect), got: %v", err)
+	}
+	if !errors.Is(err, &ParseError{Code: CodeUnknownKey, Path: "/Extra"}) {
+		t.Errorf("Expected the error to match its code and path, got: %v", err)
+	}
+	if errors.Is(err, &ParseError{Code: CodeUnknownKey, Path: "/Other"}) {
+		t.Errorf("Expected the error not to match another path, got: %v", err)
+	}
+}
+
+func TestParseError_UnwrapsTheUnderlyingError(t *testing.T) {
+	var stat Statement
+	err := stat.UnmarshalJSON([]byte(`{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3"}`))
+	_, arnErr := ParseARN("arn:aws:s3")
+
+	if errors.Unwrap(err) == nil || errors.Unwrap(err).Error() != arnErr.Error() {
+		t.Errorf("Expected: %v, got: %v", arnErr, errors.Unwrap(err))
+	}
+}
+
+func TestParseError_InvalidJSON(t *testing.T) {
+	var pd PolicyDocument
+	err := pd.UnmarshalJSON([]byte(`{"Statement": [`))
+
+	var syntaxError *json.SyntaxError
+	if !errors.Is(err, ErrInvalidJSON) || !errors.As(err, &syntaxError) {
+		t.Errorf("Expected an invalid-json error wrapping a *json.SyntaxError, got: %v", err)
+	}
+}
+
+func TestParseError_PathEscapesKeys(t *testing.T) {
+	var stat Statement
+	err := stat.UnmarshalJSON([]byte(`{"Effect":"Allow","Action":"s3:GetObject","Resource":"*","Condition":{"StringEquals":{"aws:PrincipalTag/team":{"a":"b"}}}}`))
+
+	if !errors.Is(err, &ParseError{Code: CodeInvalidType, Path: "/Condition/StringEquals/aws:PrincipalTag~1team"}) {
+		t.Errorf("Expected the path /Condition/StringEquals/aws:PrincipalTag~1team, got: %v", err)
+	}
+}
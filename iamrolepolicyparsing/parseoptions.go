@@ -0,0 +1,73 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/**
+ * ParseMode decides what happens to keys the policy grammar doesn't know (e.g. CloudFormation extras such as
+ * "Metadata", or keys added to the grammar after this package was written) in the policy, its document and its statements.
+ * An unknown principal type (e.g. a misspelled "Servic" in a Principal map) is an invalid principal, never a warning.
+ *
+ *   ParseStrict        - unknown keys are errors, as with UnmarshalJSON
+ *   ParseLenient       - unknown keys are reported as warnings and otherwise ignored
+ *   ParseIgnoreUnknown - unknown keys are ignored
+ */
+type ParseMode int
+
+const (
+	ParseStrict ParseMode = iota
+	ParseLenient
+	ParseIgnoreUnknown
+)
+
+var parseModeNames = []string{"strict", "lenient", "ignore-unknown"}
+
+func (mode ParseMode) String() string {
+	if mode < ParseStrict || mode > ParseIgnoreUnknown {
+		return fmt.Sprintf("ParseMode(%d)", int(mode))
+	}
+	return parseModeNames[mode]
+}
+
+/**
+ * Parses the name of a ParseMode as returned by its String method (e.g. "lenient").
+ */
+func ParseParseMode(name string) (ParseMode, error) {
+	for i, modeName := range parseModeNames {
+		if modeName == name {
+			return ParseMode(i), nil
+		}
+	}
+	return ParseStrict, errors.New(fmt.Sprintf(`parse mode should be one of the following: "%s"`, strings.Join(parseModeNames, `", "`)))
+}
+
+/**
+ * ParseOptions struct configures ParseIamRolePolicy. The zero value parses strictly.
+ */
+type ParseOptions struct {
+	Mode ParseMode
+}
+
+/**
+ * Parses an IamRolePolicy according to the options.
+ *
+ * Returns the policy, the warnings (unknown keys in ParseLenient mode, nil otherwise) and the first error,
+ * all of them with paths and positions as described in parseerror.go. With ParseStrict this behaves like UnmarshalJSON.
+ */
+func ParseIamRolePolicy(data []byte, options ParseOptions) (IamRolePolicy, []*ParseError, error) {
+	var policy IamRolePolicy
+	var parseErrors []error
+	var warnings []*ParseError
+	for _, err := range checkSource(data, policy.unmarshal(data)) {
+		var parseError *ParseError
+		if !errors.As(err, &parseError) || parseError.Code != CodeUnknownKey || options.Mode == ParseStrict {
+			parseErrors = append(parseErrors, err)
+		} else if options.Mode == ParseLenient {
+			warnings = append(warnings, parseError)
+		}
+	}
+	return policy, warnings, firstError(parseErrors)
+}
@@ -0,0 +1,97 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const policyWithUnknownKeys = `{"PolicyName": "name", "Metadata": {"cfn_nag": {}}, "PolicyDocument": {"Version": "2012-10-17", "Statement": [
+	{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*", "Comment": "read only"}
+]}}`
+
+func TestParseIamRolePolicy_Strict(t *testing.T) {
+	_, warnings, err := ParseIamRolePolicy([]byte(policyWithUnknownKeys), ParseOptions{Mode: ParseStrict})
+
+	if !errors.Is(err, &ParseError{Code: CodeUnknownKey, Path: "/Metadata"}) {
+		t.Errorf("Expected an unknown key error at /Metadata, got: %v", err)
+	}
+	if warnings != nil {
+		t.Errorf("Expected: <nil>, got: %v", warnings)
+	}
+}
+
+func TestParseIamRolePolicy_Lenient(t *testing.T) {
+	policy, warnings, err := ParseIamRolePolicy([]byte(policyWithUnknownKeys), ParseOptions{Mode: ParseLenient})
+	expected := []string{"/Metadata: unknown key: Metadata", "/PolicyDocument/Statement/0/Comment: unknown key: Comment"}
+
+	var messages []string
+	for _, warning := range warnings {
+		messages = append(messages, warning.Error())
+	}
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected: %q, got: %q", expected, messages)
+	}
+	if len(policy.PolicyDocument.StatementList()) != 1 || *policy.PolicyName != "name" {
+		t.Errorf("Expected the policy to be parsed, got: %v", policy)
+	}
+}
+
+func TestParseIamRolePolicy_IgnoreUnknown(t *testing.T) {
+	policy, warnings, err := ParseIamRolePolicy([]byte(policyWithUnknownKeys), ParseOptions{Mode: ParseIgnoreUnknown})
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if warnings != nil {
+		t.Errorf("Expected: <nil>, got: %v", warnings)
+	}
+	if policy.PolicyDocument == nil {
+		t.Errorf("Expected the policy to be parsed")
+	}
+}
+
+func TestParseIamRolePolicy_OtherErrorsStayErrors(t *testing.T) {
+	data := `{"PolicyName": "name", "Metadata": {}, "PolicyDocument": {"Statement": [{"Effect": "Maybe", "Action": "s3:GetObject", "Resource": "*"}]}}`
+
+	_, warnings, err := ParseIamRolePolicy([]byte(data), ParseOptions{Mode: ParseLenient})
+
+	if !errors.Is(err, ErrInvalidEffect) {
+		t.Errorf("Expected an invalid effect error, got: %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("Expected 1 warning, got: %v", warnings)
+	}
+}
+
+func TestParseIamRolePolicy_UnknownPrincipalTypeStaysAnError(t *testing.T) {
+	data := `{"PolicyName": "name", "PolicyDocument": {"Statement": [{"Effect": "Allow", "Principal": {"Servic": "ec2.amazonaws.com"}, "Action": "s3:GetObject", "Resource": "*"}]}}`
+
+	for _, mode := range []ParseMode{ParseLenient, ParseIgnoreUnknown} {
+		_, warnings, err := ParseIamRolePolicy([]byte(data), ParseOptions{Mode: mode})
+
+		if !errors.Is(err, &ParseError{Code: CodeInvalidPrincipal, Path: "/PolicyDocument/Statement/0/Principal/Servic"}) {
+			t.Errorf("%v: Expected an invalid principal error at /PolicyDocument/Statement/0/Principal/Servic, got: %v", mode, err)
+		}
+		if warnings != nil {
+			t.Errorf("%v: Expected: <nil>, got: %v", mode, warnings)
+		}
+	}
+}
+
+func TestParseMode_StringAndParse(t *testing.T) {
+	for _, mode := range []ParseMode{ParseStrict, ParseLenient, ParseIgnoreUnknown} {
+		parsed, err := ParseParseMode(mode.String())
+		if err != nil || parsed != mode {
+			t.Errorf("Expected: %v, got: %v (error: %v)", mode, parsed, err)
+		}
+	}
+
+	expectedErr := `parse mode should be one of the following: "strict", "lenient", "ignore-unknown"`
+	if _, err := ParseParseMode("loose"); err == nil || err.Error() != expectedErr {
+		t.Errorf("Expected error: %s, got: %v", expectedErr, err)
+	}
+}
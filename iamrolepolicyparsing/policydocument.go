@@ -1,8 +1,8 @@
 package iamrolepolicyparsing
 
 import (
+	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"reflect"
 )
@@ -10,13 +10,28 @@ import (
 /**
  * PolicyDocument struct represents the policy document in an IAM role policy.
  *
+ * The "Statement" key may hold either an array of statements or a single statement object.
+ * Either way Statements holds the parsed statements, and SingleStatement is true if the JSON used the single object form.
+ *
  * for grammar see https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_grammar.html
  * see statement.go for the Statement struct
  */
 type PolicyDocument struct {
-	Version    *string      `json:"Version"`
-	Id         *string      `json:"Id"`
-	Statements *[]Statement `json:"Statement"`
+	Version         *string      `json:"Version"`
+	Id              *string      `json:"Id"`
+	Statements      *[]Statement `json:"Statement"`
+	SingleStatement bool         `json:"-"`
+}
+
+/**
+ * Returns the statements of the document as a slice, regardless of the form used in the JSON.
+ * Returns nil if the document is nil or has no statements.
+ */
+func (pd *PolicyDocument) StatementList() []Statement {
+	if pd == nil || pd.Statements == nil {
+		return nil
+	}
+	return *pd.Statements
 }
 
 func (pd *PolicyDocument) String() string {
@@ -61,7 +76,18 @@ func (this PolicyDocument) Equals(other interface{}) bool {
 	return true
 }
 
+/**
+ * Parses a policy document.
+ *
+ * Errors are *ParseError values (see parseerror.go) with paths relative to the document, e.g. "/Statement/3/Principal".
+ * Only the first error is returned, see IamRolePolicy.Validate for getting all of them.
+ */
 func (pd *PolicyDocument) UnmarshalJSON(data []byte) error {
+	return firstError(checkSource(data, pd.unmarshal(data)))
+}
+
+// Parses the policy document, returning every error found
+func (pd *PolicyDocument) unmarshal(data []byte) []error {
 	// decode.go/line 117
 	// By convention, to approximate the behavior of [Unmarshal] itself,
 	// Unmarshalers implement UnmarshalJSON([]byte("null")) as a no-op.
@@ -69,38 +95,232 @@ func (pd *PolicyDocument) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
-	var m map[string]interface{}
+	var m map[string]json.RawMessage
 	if err := json.Unmarshal(data, &m); err != nil {
-		return err
+		return []error{jsonParseError("", err)}
 	}
 
+	var errs []error
 	// Verify that the JSON object has only the expected keys
-	for key := range m {
+	for _, key := range sortedKeys(m) {
 		switch key {
 		case "Version", "Id", "Statement":
 			continue
 		default:
-			return fmt.Errorf("unexpected key in JSON: %s", key)
+			errs = append(errs, newParseError(CodeUnknownKey, jsonPointer("", key), fmt.Sprintf("unexpected key in JSON: %s", key)))
 		}
 	}
 
-	// Unmarshal the JSON using the default Unmarshaler
-	type Alias PolicyDocument
-	aux := &struct {
-		*Alias
-	}{
-		Alias: (*Alias)(pd),
+	if err := unmarshalOptionalString(m, "Version", &pd.Version); err != nil {
+		errs = append(errs, err)
 	}
-	if err := json.Unmarshal(data, &aux); err != nil {
-		return errors.New(fmt.Sprintf("error unmarshalling a statement: %s", err.Error()))
+	if err := unmarshalOptionalString(m, "Id", &pd.Id); err != nil {
+		errs = append(errs, err)
 	}
+	errs = append(errs, pd.unmarshalStatements(m["Statement"])...)
 
 	if pd.Version != nil && *pd.Version != "2012-10-17" && *pd.Version != "2008-10-17" {
-		return errors.New("Version must be 2012-10-17 or 2008-10-17")
+		errs = append(errs, newParseError(CodeInvalidVersion, "/Version", "Version must be 2012-10-17 or 2008-10-17"))
+	}
+	if isAbsentOrNull(m, "Statement") {
+		errs = append(errs, newParseError(CodeMissingKey, "/Statement", "Statements array is required"))
+	}
+	errs = append(errs, pd.duplicateSidErrors()...)
+	// a document without Version is evaluated as Version 2008-10-17, which has no policy variables
+	if pd.Version == nil || *pd.Version == "2008-10-17" {
+		version := "in Version 2008-10-17"
+		if pd.Version == nil {
+			version = "without a Version (which defaults to 2008-10-17)"
+		}
+		for i, statement := range pd.StatementList() {
+			if variables := statement.PolicyVariables(); len(variables) > 0 {
+				errs = append(errs, newParseError(CodeUnsupportedPolicyVariable, pd.statementPath(i), fmt.Sprintf(`policy variables are not supported %s, found "%s"`, version, variables[0])))
+			}
+		}
+	}
+
+	return errs
+}
+
+/**
+ * Writes the policy document back in the policy grammar.
+ *
+ * The statements are written as a single object if SingleStatement is set and there is exactly one statement,
+ * and as an array otherwise. Absent values are omitted.
+ */
+func (pd PolicyDocument) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		Version   *string     `json:"Version,omitempty"`
+		Id        *string     `json:"Id,omitempty"`
+		Statement interface{} `json:"Statement,omitempty"`
+	}{
+		Version: pd.Version,
+		Id:      pd.Id,
+	}
+
+	if pd.Statements != nil {
+		if pd.SingleStatement && len(*pd.Statements) == 1 {
+			aux.Statement = (*pd.Statements)[0]
+		} else {
+			aux.Statement = *pd.Statements
+		}
+	}
+
+	return json.Marshal(aux)
+}
+
+// Sets Statements from the raw value of the "Statement" key, which is either a statement object or an array of them.
+// Statements that fail to parse are kept as far as they were parsed, so that the document can still be checked.
+func (pd *PolicyDocument) unmarshalStatements(data json.RawMessage) []error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return nil
+	}
+
+	if trimmed[0] == '{' {
+		pd.SingleStatement = true
+		var statement Statement
+		errs := statement.unmarshal(trimmed)
+		pd.Statements = &[]Statement{statement}
+		return prefixErrorPaths(pd.statementPath(0), errs)
+	}
+
+	pd.SingleStatement = false
+	var rawStatements []json.RawMessage
+	if err := json.Unmarshal(trimmed, &rawStatements); err != nil {
+		pd.Statements = &[]Statement{}
+		return []error{newParseError(CodeInvalidType, "/Statement", "Statement should be a statement object or an array of them")}
+	}
+	var errs []error
+	statements := []Statement{}
+	for i, rawStatement := range rawStatements {
+		var statement Statement
+		errs = append(errs, prefixErrorPaths(pd.statementPath(i), statement.unmarshal(bytes.TrimSpace(rawStatement)))...)
+		statements = append(statements, statement)
+	}
+	pd.Statements = &statements
+	return errs
+}
+
+// Sids have to be unique within a document, every statement reusing the Sid of an earlier one is reported
+func (pd *PolicyDocument) duplicateSidErrors() []error {
+	var errs []error
+	firstStatements := map[string]int{}
+	for i, statement := range pd.StatementList() {
+		if statement.Sid == nil || *statement.Sid == "" {
+			continue
+		}
+		if first, ok := firstStatements[*statement.Sid]; ok {
+			errs = append(errs, newParseError(CodeDuplicateSid, pd.statementPath(i)+"/Sid", fmt.Sprintf(`sid "%s" is already used by statement %d`, *statement.Sid, first)))
+			continue
+		}
+		firstStatements[*statement.Sid] = i
 	}
-	if pd.Statements == nil {
-		return errors.New("Statements array is required")
+	return errs
+}
+
+// The path of the i-th statement, "/Statement" if the document uses the single object form
+func (pd *PolicyDocument) statementPath(i int) string {
+	if pd.SingleStatement {
+		return "/Statement"
 	}
+	return jsonPointer("", "Statement", i)
+}
 
+// Sets target from the string value of the key, leaving it nil if the key is absent or null
+func unmarshalOptionalString(m map[string]json.RawMessage, key string, target **string) error {
+	raw, ok := m[key]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return newParseError(CodeInvalidType, jsonPointer("", key), fmt.Sprintf("%s should be a string", key))
+	}
 	return nil
 }
+
+// Whether the key is absent from the object or null
+func isAbsentOrNull(m map[string]json.RawMessage, key string) bool {
+	raw, ok := m[key]
+	return !ok || string(bytes.TrimSpace(raw)) == "null"
+}
+
+/**
+ * Returns whether no statement in the document has a resource that is a wildcard ('*').
+ * Resource arrays are inspected element by element.
+ *
+ * see (pd *PolicyDocument)StatementsWithWildcardResource() []StatementFinding
+ */
+func (pd *PolicyDocument) NoStatementHasWildcardResource() bool {
+	return len(pd.StatementsWithWildcardResource()) == 0
+}
+
+/**
+ * Returns a finding for every Resource value in the document that is a wildcard ('*'),
+ * pointing at the statement and the element of the Resource array that triggered it.
+ *
+ * see (stat Statement)wildcardResourceIndexes() []int in statement.go
+ */
+func (pd *PolicyDocument) StatementsWithWildcardResource() []StatementFinding {
+	return findStatementElements(pd.StatementList(), Statement.wildcardResourceIndexes, Statement.resourceStrings)
+}
+
+/**
+ * Returns a finding for every Resource value of an "Allow" statement that is broader than the given limit
+ * (see resourcebreadth.go), so that e.g. a CI job can fail on anything broader than BreadthAccountWide.
+ *
+ * A statement with NotResource is reported once with ElementIndex -1 and Value "NotResource",
+ * unless the limit is BreadthGlobal.
+ */
+func (pd *PolicyDocument) StatementsWithResourceBroaderThan(limit ResourceBreadth) []StatementFinding {
+	var findings []StatementFinding
+	for i, statement := range pd.StatementList() {
+		if statement.Effect == nil || *statement.Effect != "Allow" {
+			continue
+		}
+		if !statement.Resource {
+			if BreadthGlobal > limit {
+				findings = append(findings, StatementFinding{StatementIndex: i, Sid: statement.Sid, ElementIndex: -1, Value: "NotResource"})
+			}
+			continue
+		}
+		for elementIndex, resource := range statement.resourceStrings() {
+			if ClassifyResource(resource) > limit {
+				findings = append(findings, StatementFinding{
+					StatementIndex: i,
+					Sid:            statement.Sid,
+					ElementIndex:   elementIndex,
+					Value:          resource,
+				})
+			}
+		}
+	}
+	return findings
+}
+
+/**
+ * Returns whether no "Allow" statement in the document has an action that is a wildcard ('*').
+ *
+ * see (pd *PolicyDocument)StatementsWithWildcardAction() []StatementFinding
+ */
+func (pd *PolicyDocument) NoStatementHasWildcardAction() bool {
+	return len(pd.StatementsWithWildcardAction()) == 0
+}
+
+/**
+ * Returns a finding for every Action value of an "Allow" statement that is a wildcard ('*'),
+ * pointing at the statement and the element of the Action array that triggered it.
+ */
+func (pd *PolicyDocument) StatementsWithWildcardAction() []StatementFinding {
+	statements := allowStatements(pd.StatementList())
+	return findStatementElements(statements, Statement.wildcardActionIndexes, Statement.actionStrings)
+}
+
+/**
+ * Returns a finding for every Action value of an "Allow" statement that covers a whole service (e.g. "iam:*"),
+ * pointing at the statement and the element of the Action array that triggered it.
+ */
+func (pd *PolicyDocument) StatementsWithServiceWideAction() []StatementFinding {
+	statements := allowStatements(pd.StatementList())
+	return findStatementElements(statements, Statement.serviceWideActionIndexes, Statement.actionStrings)
+}
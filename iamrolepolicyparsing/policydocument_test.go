@@ -211,7 +211,7 @@ func TestPolicyDocument_String(t *testing.T) {
 func TestPolicyDocument_UnmarshalJSONNoEffectInAStatement(t *testing.T) {
 	data := []byte(`{"Version":"2012-10-17","Id":"id","Statement":[{"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"},{"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}]}`)
 	var pd PolicyDocument
-	expectedErr := errors.New("error unmarshalling a statement: effect is absent or a non-string")
+	expectedErr := &ParseError{Code: CodeMissingKey, Path: "/Statement/0/Effect", Message: "effect is absent or a non-string", Line: 1, Column: 48}
 
 	err := pd.UnmarshalJSON(data)
 
@@ -223,7 +223,7 @@ func TestPolicyDocument_UnmarshalJSONNoEffectInAStatement(t *testing.T) {
 func TestPolicyDocument_UnmarshalJSONNoStatementBlock(t *testing.T) {
 	data := []byte(`{"Version":"2012-10-17","Id":"id"}`)
 	var pd PolicyDocument
-	expectedErr := errors.New("Statements array is required")
+	expectedErr := &ParseError{Code: CodeMissingKey, Path: "/Statement", Message: "Statements array is required", Line: 1, Column: 1}
 
 	err := pd.UnmarshalJSON(data)
 
@@ -344,10 +344,141 @@ func TestUnmarshal_PolicyDocumentWhenNull(t *testing.T) {
 func TestPolicyDocument_UnmarshalJSONInvalidKey(t *testing.T) {
 	data := []byte(`{"invalid_key": "value"}`)
 	var pd PolicyDocument
-	expectedErr := errors.New("unexpected key in JSON: invalid_key")
+	expectedErr := &ParseError{Code: CodeUnknownKey, Path: "/invalid_key", Message: "unexpected key in JSON: invalid_key", Line: 1, Column: 2}
 	err := pd.UnmarshalJSON(data)
 
 	if !reflect.DeepEqual(expectedErr, err) {
 		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
 	}
 }
+
+func TestPolicyDocument_UnmarshalJSONSingleStatementObject(t *testing.T) {
+	data := []byte(`{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}}`)
+	var pd PolicyDocument
+	err := pd.UnmarshalJSON(data)
+
+	expected := PolicyDocument{
+		Version: stringOf("2012-10-17"),
+		Statements: &[]Statement{{
+			Effect:        stringOf("Allow"),
+			Principal:     false,
+			Action:        true,
+			Resource:      true,
+			ActionValue:   "s3:ListBucket",
+			ResourceValue: "arn:aws:s3:::example-bucket",
+		}},
+	}
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if !pd.Equals(expected) {
+		t.Errorf("Expected: \n%v\n\t, got: \n%v", expected.String(), pd.String())
+	}
+	if !pd.SingleStatement {
+		t.Errorf("Expected SingleStatement to be true")
+	}
+}
+
+func TestPolicyDocument_UnmarshalJSONStatementArrayIsNotSingleStatement(t *testing.T) {
+	data := []byte(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}]}`)
+	var pd PolicyDocument
+	err := pd.UnmarshalJSON(data)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if pd.SingleStatement {
+		t.Errorf("Expected SingleStatement to be false")
+	}
+}
+
+func TestPolicyDocument_UnmarshalJSONInvalidSingleStatementObject(t *testing.T) {
+	data := []byte(`{"Version":"2012-10-17","Statement":{"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}}`)
+	var pd PolicyDocument
+	expectedErr := &ParseError{Code: CodeMissingKey, Path: "/Statement/Effect", Message: "effect is absent or a non-string", Line: 1, Column: 25}
+
+	err := pd.UnmarshalJSON(data)
+
+	if !reflect.DeepEqual(expectedErr, err) {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+	}
+}
+
+func TestPolicyDocument_StatementList(t *testing.T) {
+	statements := []Statement{{Effect: stringOf("Allow")}, {Effect: stringOf("Deny")}}
+	pd := PolicyDocument{Statements: &statements}
+
+	if !reflect.DeepEqual(pd.StatementList(), statements) {
+		t.Errorf("Expected: %v, got: %v", statements, pd.StatementList())
+	}
+}
+
+func TestPolicyDocument_StatementListWhenNil(t *testing.T) {
+	pd := PolicyDocument{}
+
+	if pd.StatementList() != nil {
+		t.Errorf("Expected: <nil>, got: %v", pd.StatementList())
+	}
+}
+
+func TestPolicyDocument_MarshalJSONSingleStatement(t *testing.T) {
+	data := []byte(`{"Version":"2012-10-17","Statement":{"Effect":"Allow","Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}}`)
+	var pd PolicyDocument
+	if err := pd.UnmarshalJSON(data); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+
+	marshalled, err := json.Marshal(pd)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if string(marshalled) != string(data) {
+		t.Errorf("Expected: \n%s\n\t, got: \n%s", string(data), string(marshalled))
+	}
+}
+
+func TestPolicyDocument_MarshalJSONStatementArray(t *testing.T) {
+	data := []byte(`{"Version":"2008-10-17","Id":"i2d","Statement":[{"Effect":"Allow","Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}]}`)
+	var pd PolicyDocument
+	if err := pd.UnmarshalJSON(data); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+
+	marshalled, err := json.Marshal(pd)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if string(marshalled) != string(data) {
+		t.Errorf("Expected: \n%s\n\t, got: \n%s", string(data), string(marshalled))
+	}
+}
+
+func TestPolicyDocument_UnmarshalJSONDuplicateSid(t *testing.T) {
+	data := []byte(`{"Version":"2012-10-17","Statement":[
+		{"Sid":"Read","Effect":"Allow","Action":"s3:GetObject","Resource":"*"},
+		{"Sid":"Write","Effect":"Allow","Action":"s3:PutObject","Resource":"*"},
+		{"Sid":"Read","Effect":"Allow","Action":"s3:ListBucket","Resource":"*"}
+	]}`)
+	var pd PolicyDocument
+	expectedErr := &ParseError{Code: CodeDuplicateSid, Path: "/Statement/2/Sid", Message: `sid "Read" is already used by statement 0`, Line: 4, Column: 4}
+
+	err := pd.UnmarshalJSON(data)
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %+v, got: %+v", expectedErr, err)
+	}
+}
+
+func TestPolicyDocument_UnmarshalJSONStatementsWithoutSid(t *testing.T) {
+	data := []byte(`{"Version":"2012-10-17","Statement":[
+		{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"},
+		{"Effect":"Allow","Action":"s3:PutObject","Resource":"*"}
+	]}`)
+	var pd PolicyDocument
+
+	if err := pd.UnmarshalJSON(data); err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+}
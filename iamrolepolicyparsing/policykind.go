@@ -0,0 +1,132 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/**
+ * PolicyKind is what a policy document is attached to, which decides whether its statements may name a principal.
+ *
+ *   PolicyKindIdentity                - attached to a role, user or group (e.g. an AWS::IAM::RolePolicy), Principal and NotPrincipal are not allowed
+ *   PolicyKindTrust                   - the assume role policy of a role, every statement needs a Principal or NotPrincipal
+ *   PolicyKindResource                - attached to a resource (e.g. an S3 bucket policy), every statement needs a Principal or NotPrincipal
+ *   PolicyKindServiceControl          - a service control policy (SCP) of an AWS Organizations account or OU, Principal and NotPrincipal are not allowed
+ *   PolicyKindServiceControlLegacy    - an SCP in the older syntax, whose "Allow" statements can't have a Condition, NotAction
+ *                                       or NotResource and whose Resource has to be "*"
+ *   PolicyKindPermissionsBoundary     - the permissions boundary of a role or user, Principal and NotPrincipal are not allowed
+ *
+ * SCPs and permissions boundaries never grant access by themselves, they only limit what identity policies grant
+ * (see EffectivePolicy).
+ *
+ * for SCP syntax see https://docs.aws.amazon.com/organizations/latest/userguide/orgs_manage_policies_scps_syntax.html
+ */
+type PolicyKind int
+
+const (
+	PolicyKindIdentity PolicyKind = iota
+	PolicyKindTrust
+	PolicyKindResource
+	PolicyKindServiceControl
+	PolicyKindServiceControlLegacy
+	PolicyKindPermissionsBoundary
+)
+
+var policyKindNames = []string{"identity", "trust", "resource", "service-control", "service-control-legacy", "permissions-boundary"}
+
+// The kinds as used in error messages, e.g. "Principal is not allowed in an identity policy"
+var policyKindDescriptions = []string{
+	"an identity policy",
+	"a trust policy",
+	"a resource policy",
+	"a service control policy",
+	"a service control policy (legacy syntax)",
+	"a permissions boundary",
+}
+
+func (kind PolicyKind) String() string {
+	if kind < PolicyKindIdentity || kind > PolicyKindPermissionsBoundary {
+		return fmt.Sprintf("PolicyKind(%d)", int(kind))
+	}
+	return policyKindNames[kind]
+}
+
+// Whether the statements of a policy of this kind have to name a principal, otherwise they may not
+func (kind PolicyKind) requiresPrincipal() bool {
+	return kind == PolicyKindTrust || kind == PolicyKindResource
+}
+
+/**
+ * Parses a policy document of the given kind, returning every error found like IamRolePolicy.Validate.
+ * On top of the checks of UnmarshalJSON, Principal and NotPrincipal have to be present or absent as the kind requires,
+ * a trust policy is checked like TrustPolicy.Validate does and a legacy SCP against the restrictions of its syntax.
+ */
+func (pd *PolicyDocument) ValidateKind(data []byte, kind PolicyKind) []*ParseError {
+	return toParseErrors(checkSource(data, pd.unmarshalKind(data, kind)))
+}
+
+// Parses the document and checks it against the kind, returning every error found
+func (pd *PolicyDocument) unmarshalKind(data []byte, kind PolicyKind) []error {
+	var errs []error
+	for _, err := range pd.unmarshal(data) {
+		// the statements of a trust policy have no Resource, see trustpolicy.go
+		if kind == PolicyKindTrust && errors.Is(err, ErrMissingKey) && strings.HasSuffix(err.(*ParseError).Path, "/Resource") {
+			continue
+		}
+		errs = append(errs, err)
+	}
+	errs = append(errs, pd.kindErrors(kind)...)
+	if kind == PolicyKindTrust {
+		errs = append(errs, pd.trustErrors()...)
+	}
+	if kind == PolicyKindServiceControlLegacy {
+		errs = append(errs, pd.legacyServiceControlErrors()...)
+	}
+	return errs
+}
+
+// Checks the Principal and NotPrincipal elements of the statements against the kind of the document
+func (pd *PolicyDocument) kindErrors(kind PolicyKind) []error {
+	var errs []error
+	for i, statement := range pd.StatementList() {
+		if kind.requiresPrincipal() && statement.PrincipalValue == nil {
+			errs = append(errs, newParseError(CodeMissingKey, pd.statementPath(i)+"/Principal", fmt.Sprintf("Principal or NotPrincipal is required in %s", policyKindDescriptions[kind])))
+		} else if !kind.requiresPrincipal() && statement.PrincipalValue != nil {
+			key := "Principal"
+			if !statement.Principal {
+				key = "NotPrincipal"
+			}
+			errs = append(errs, newParseError(CodePrincipalNotAllowed, pd.statementPath(i)+"/"+key, fmt.Sprintf("%s is not allowed in %s", key, policyKindDescriptions[kind])))
+		}
+	}
+	return errs
+}
+
+// Checks the "Allow" statements of an SCP in the legacy syntax, which only lists the allowed actions
+func (pd *PolicyDocument) legacyServiceControlErrors() []error {
+	var errs []error
+	const restriction = "is not allowed in an Allow statement of a service control policy (legacy syntax)"
+	for i, statement := range pd.StatementList() {
+		if statement.Effect == nil || *statement.Effect != "Allow" {
+			continue
+		}
+		path := pd.statementPath(i)
+		if statement.ConditionMap != nil {
+			errs = append(errs, newParseError(CodeInvalidCondition, path+"/Condition", "Condition "+restriction))
+		}
+		if statement.ActionValue != nil && !statement.Action {
+			errs = append(errs, newParseError(CodeInvalidAction, path+"/NotAction", "NotAction "+restriction))
+		}
+		if statement.ResourceValue != nil && !statement.Resource {
+			errs = append(errs, newParseError(CodeInvalidResource, path+"/NotResource", "NotResource "+restriction))
+			continue
+		}
+		for j, resource := range statement.resourceStrings() {
+			if resource != "*" {
+				errs = append(errs, newParseError(CodeInvalidResource, path+elementPath("Resource", statement.ResourceValue, j), fmt.Sprintf(`resource "%s" %s, it should be "*"`, resource, restriction)))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,110 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const documentWithAndWithoutPrincipal = `{"Version": "2012-10-17", "Statement": [
+	{"Effect": "Allow", "Principal": {"Service": ["ec2.amazonaws.com"]}, "Action": "s3:GetObject", "Resource": "*"},
+	{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}
+]}`
+
+func TestIamRolePolicy_UnmarshalWithPrincipal(t *testing.T) {
+	data := `{"PolicyName": "name", "PolicyDocument": {"Version": "2012-10-17", "Statement": {"Effect": "Deny", "NotPrincipal": {"AWS": ["123456789012"]}, "Action": "s3:*", "Resource": "*"}}}`
+	var policy IamRolePolicy
+	expectedErr := &ParseError{Code: CodePrincipalNotAllowed, Path: "/PolicyDocument/Statement/NotPrincipal", Message: "NotPrincipal is not allowed in an identity policy", Line: 1, Column: 100}
+
+	err := policy.UnmarshalJSON([]byte(data))
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %+v, got: %+v", expectedErr, err)
+	}
+	if !errors.Is(err, ErrPrincipalNotAllowed) {
+		t.Errorf("Expected errors.Is(err, ErrPrincipalNotAllowed), got: %v", err)
+	}
+}
+
+func TestPolicyDocument_ValidateKind(t *testing.T) {
+	expected := map[PolicyKind][]string{
+		PolicyKindIdentity: {"/Statement/0/Principal: Principal is not allowed in an identity policy"},
+		PolicyKindTrust: {
+			"/Statement/1/Principal: Principal or NotPrincipal is required in a trust policy",
+			"/Statement/0/Resource: Resource is not allowed in a trust policy",
+			`/Statement/0/Action: action "s3:GetObject" is not allowed in a trust policy, it should be sts:AssumeRole, sts:AssumeRoleWithSAML, sts:AssumeRoleWithWebIdentity or sts:TagSession`,
+			"/Statement/1/Resource: Resource is not allowed in a trust policy",
+			`/Statement/1/Action: action "s3:GetObject" is not allowed in a trust policy, it should be sts:AssumeRole, sts:AssumeRoleWithSAML, sts:AssumeRoleWithWebIdentity or sts:TagSession`,
+		},
+		PolicyKindResource: {"/Statement/1/Principal: Principal or NotPrincipal is required in a resource policy"},
+	}
+
+	for kind, expectedMessages := range expected {
+		var pd PolicyDocument
+		var messages []string
+		for _, parseError := range pd.ValidateKind([]byte(documentWithAndWithoutPrincipal), kind) {
+			messages = append(messages, parseError.Error())
+		}
+		if !reflect.DeepEqual(messages, expectedMessages) {
+			t.Errorf("%s: Expected: %q, got: %q", kind, expectedMessages, messages)
+		}
+	}
+}
+
+func TestPolicyDocument_ValidateKindWhenValid(t *testing.T) {
+	data := []byte(`{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"}]}`)
+	var pd PolicyDocument
+
+	parseErrors := pd.ValidateKind(data, PolicyKindResource)
+
+	if parseErrors != nil {
+		t.Errorf("Expected: <nil>, got: %v", parseErrors)
+	}
+	if len(pd.StatementList()) != 1 {
+		t.Errorf("Expected the document to be parsed, got: %v", pd.String())
+	}
+}
+
+func TestPolicyKind_String(t *testing.T) {
+	if PolicyKindTrust.String() != "trust" || PolicyKind(42).String() != "PolicyKind(42)" {
+		t.Errorf("Expected: trust and PolicyKind(42), got: %s and %s", PolicyKindTrust, PolicyKind(42))
+	}
+}
+
+func TestPolicyDocument_ValidateKindServiceControlLegacy(t *testing.T) {
+	data := `{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Action": "*", "Resource": "*"},
+		{"Effect": "Allow", "NotAction": "iam:*", "Resource": ["*", "arn:aws:s3:::bucket"], "Condition": {"StringEquals": {"aws:RequestedRegion": "eu-west-1"}}},
+		{"Effect": "Allow", "Action": "s3:*", "NotResource": "arn:aws:s3:::secret"},
+		{"Effect": "Deny", "Action": "*", "Resource": "*", "Condition": {"StringNotEquals": {"aws:RequestedRegion": "eu-west-1"}}}
+	]}`
+	restriction := "is not allowed in an Allow statement of a service control policy (legacy syntax)"
+	expected := []string{
+		"/Statement/1/Condition: Condition " + restriction,
+		"/Statement/1/NotAction: NotAction " + restriction,
+		`/Statement/1/Resource/1: resource "arn:aws:s3:::bucket" ` + restriction + `, it should be "*"`,
+		"/Statement/2/NotResource: NotResource " + restriction,
+	}
+	var pd PolicyDocument
+
+	messages := validationMessages(pd.ValidateKind([]byte(data), PolicyKindServiceControlLegacy))
+
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected: %q, got: %q", expected, messages)
+	}
+	if parseErrors := pd.ValidateKind([]byte(data), PolicyKindServiceControl); parseErrors != nil {
+		t.Errorf("Expected: <nil>, got: %v", parseErrors)
+	}
+}
+
+func TestPolicyDocument_ValidateKindPermissionsBoundary(t *testing.T) {
+	data := `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:*", "Resource": "*"}]}`
+	expected := []string{"/Statement/0/Principal: Principal is not allowed in a permissions boundary"}
+	var pd PolicyDocument
+
+	messages := validationMessages(pd.ValidateKind([]byte(data), PolicyKindPermissionsBoundary))
+
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected: %q, got: %q", expected, messages)
+	}
+}
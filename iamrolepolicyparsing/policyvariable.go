@@ -0,0 +1,228 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/**
+ * PolicyVariable struct represents a policy variable such as ${aws:username} in a Resource or Condition value.
+ *
+ * Name is the condition key the variable is replaced with (e.g. "aws:username") and Default is the value used
+ * when the key is absent from the request context, as in ${aws:username, 'none'} (nil if there is no default).
+ * The escapes ${*}, ${?} and ${$} are represented as variables named "*", "?" and "$" (see IsEscape).
+ *
+ * Policy variables are only supported in policy documents with Version "2012-10-17".
+ *
+ * for grammar see https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_variables.html
+ */
+type PolicyVariable struct {
+	Name    string
+	Default *string
+}
+
+func (variable PolicyVariable) String() string {
+	if variable.Default != nil {
+		return fmt.Sprintf("${%s, '%s'}", variable.Name, *variable.Default)
+	}
+	return "${" + variable.Name + "}"
+}
+
+/**
+ * Returns whether the variable is one of the ${*}, ${?} and ${$} escapes, which stand for the literal character.
+ */
+func (variable PolicyVariable) IsEscape() bool {
+	return variable.Name == "*" || variable.Name == "?" || variable.Name == "$"
+}
+
+// A piece of a string containing policy variables, either plain text or a variable
+type policyVariablePart struct {
+	text     string
+	variable *PolicyVariable
+}
+
+/**
+ * Returns the policy variables used in s, in order of appearance, including the ${*}, ${?} and ${$} escapes.
+ *
+ * Returns an error if a variable is not terminated, if its name isn't a condition key of the form
+ * "prefix:key" (e.g. "aws:username" or "aws:PrincipalTag/team") or if its default value isn't single-quoted.
+ */
+func ParsePolicyVariables(s string) ([]PolicyVariable, error) {
+	parts, err := parsePolicyVariableParts(s)
+	if err != nil {
+		return nil, err
+	}
+	var variables []PolicyVariable
+	for _, part := range parts {
+		if part.variable != nil {
+			variables = append(variables, *part.variable)
+		}
+	}
+	return variables, nil
+}
+
+/**
+ * Replaces the policy variables in s with their values from the request context, falling back to their defaults.
+ * The escapes are replaced with the character they stand for.
+ *
+ * Returns false if a variable has neither a value in the context nor a default, or if s doesn't parse.
+ */
+func SubstitutePolicyVariables(s string, context RequestContext) (string, bool) {
+	pattern, ok := substitutePolicyVariablePattern(s, context)
+	if !ok {
+		return "", false
+	}
+	var builder strings.Builder
+	for _, patternRune := range pattern {
+		builder.WriteRune(patternRune.r)
+	}
+	return builder.String(), true
+}
+
+// Like SubstitutePolicyVariables, but keeps the wildcards of the text outside variables as wildcards
+// while everything coming from a variable only matches literally
+func substitutePolicyVariablePattern(s string, context RequestContext) ([]patternRune, bool) {
+	parts, err := parsePolicyVariableParts(s)
+	if err != nil {
+		return nil, false
+	}
+
+	var pattern []patternRune
+	for _, part := range parts {
+		if part.variable == nil {
+			pattern = append(pattern, toPattern(part.text)...)
+			continue
+		}
+		if part.variable.IsEscape() {
+			pattern = append(pattern, toLiteralPattern(part.variable.Name)...)
+			continue
+		}
+		if values := context.lookup(part.variable.Name); len(values) > 0 {
+			pattern = append(pattern, toLiteralPattern(values[0])...)
+		} else if part.variable.Default != nil {
+			pattern = append(pattern, toLiteralPattern(*part.variable.Default)...)
+		} else {
+			return nil, false
+		}
+	}
+	return pattern, true
+}
+
+func parsePolicyVariableParts(s string) ([]policyVariablePart, error) {
+	var parts []policyVariablePart
+	rest := s
+	for {
+		start := strings.Index(rest, "${")
+		if start == -1 {
+			break
+		}
+		end := strings.Index(rest[start:], "}")
+		if end == -1 {
+			return nil, errors.New(fmt.Sprintf(`unterminated policy variable in "%s"`, s))
+		}
+		end += start
+
+		variable, err := parsePolicyVariable(rest[start+2 : end])
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf(`invalid policy variable "%s" in "%s": %s`, rest[start:end+1], s, err.Error()))
+		}
+		if start > 0 {
+			parts = append(parts, policyVariablePart{text: rest[:start]})
+		}
+		parts = append(parts, policyVariablePart{variable: &variable})
+		rest = rest[end+1:]
+	}
+	if rest != "" {
+		parts = append(parts, policyVariablePart{text: rest})
+	}
+	return parts, nil
+}
+
+// Parses the inside of ${...}
+func parsePolicyVariable(inner string) (PolicyVariable, error) {
+	name, defaultValue, hasDefault := strings.Cut(inner, ",")
+	name = strings.TrimSpace(name)
+
+	variable := PolicyVariable{Name: name}
+	if variable.IsEscape() {
+		if hasDefault {
+			return PolicyVariable{}, errors.New("escapes can't have a default value")
+		}
+		return variable, nil
+	}
+
+	prefix, key, found := strings.Cut(name, ":")
+	if !found || prefix == "" || key == "" || !isConditionKey(name) {
+		return PolicyVariable{}, errors.New(`the name should be a condition key such as "aws:username"`)
+	}
+	if hasDefault {
+		defaultValue = strings.TrimSpace(defaultValue)
+		if len(defaultValue) < 2 || defaultValue[0] != '\'' || defaultValue[len(defaultValue)-1] != '\'' {
+			return PolicyVariable{}, errors.New("the default value should be enclosed in single quotes")
+		}
+		defaultValue = defaultValue[1 : len(defaultValue)-1]
+		variable.Default = &defaultValue
+	}
+	return variable, nil
+}
+
+func isConditionKey(s string) bool {
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune(":/._-", c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Splits s around sep into at most n parts, ignoring separators inside ${...}
+func splitOutsideVariables(s string, sep byte, n int) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s) && len(parts) < n-1; i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+		case s[i] == '}' && depth > 0:
+			depth--
+		case s[i] == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// Returns the index of the first of the chars in s outside ${...}, or -1
+func indexOutsideVariables(s string, chars string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+		case s[i] == '}' && depth > 0:
+			depth--
+		case depth == 0 && strings.IndexByte(chars, s[i]) != -1:
+			return i
+		}
+	}
+	return -1
+}
+
+// Replaces every policy variable in s with the given placeholder
+func replacePolicyVariables(s string, placeholder string) string {
+	parts, err := parsePolicyVariableParts(s)
+	if err != nil {
+		return s
+	}
+	var builder strings.Builder
+	for _, part := range parts {
+		if part.variable != nil {
+			builder.WriteString(placeholder)
+		} else {
+			builder.WriteString(part.text)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,227 @@
+package iamrolepolicyparsing
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPolicyVariable_Parse(t *testing.T) {
+	none := "none"
+	expected := []PolicyVariable{
+		{Name: "aws:username"},
+		{Name: "aws:PrincipalTag/team", Default: &none},
+		{Name: "*"},
+	}
+
+	variables, err := ParsePolicyVariables("home/${aws:username}/${aws:PrincipalTag/team, 'none'}/${*}")
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if !reflect.DeepEqual(variables, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, variables)
+	}
+}
+
+func TestPolicyVariable_ParseWhenNone(t *testing.T) {
+	variables, err := ParsePolicyVariables("arn:aws:s3:::bucket/$HOME/{x}")
+
+	if err != nil || variables != nil {
+		t.Errorf("Expected no variables and no error, got: %v, %v", variables, err)
+	}
+}
+
+func TestPolicyVariable_ParseMalformed(t *testing.T) {
+	tests := map[string]string{
+		"home/${aws:username":        `unterminated policy variable in "home/${aws:username"`,
+		"home/${username}":           `invalid policy variable "${username}" in "home/${username}": the name should be a condition key such as "aws:username"`,
+		"home/${aws:user name}":      `invalid policy variable "${aws:user name}" in "home/${aws:user name}": the name should be a condition key such as "aws:username"`,
+		"home/${aws:username, none}": `invalid policy variable "${aws:username, none}" in "home/${aws:username, none}": the default value should be enclosed in single quotes`,
+		"home/${*, 'x'}":             `invalid policy variable "${*, 'x'}" in "home/${*, 'x'}": escapes can't have a default value`,
+	}
+
+	for s, expected := range tests {
+		_, err := ParsePolicyVariables(s)
+		if err == nil || err.Error() != expected {
+			t.Errorf("%s: Expected error: %s, got: %v", s, expected, err)
+		}
+	}
+}
+
+func TestPolicyVariable_String(t *testing.T) {
+	none := "none"
+	tests := map[string]PolicyVariable{
+		"${aws:username}":         {Name: "aws:username"},
+		"${aws:username, 'none'}": {Name: "aws:username", Default: &none},
+		"${$}":                    {Name: "$"},
+	}
+
+	for expected, variable := range tests {
+		if variable.String() != expected {
+			t.Errorf("Expected: %s, got: %s", expected, variable.String())
+		}
+	}
+}
+
+func TestPolicyVariable_Substitute(t *testing.T) {
+	context := RequestContext{"aws:username": {"alice"}}
+	tests := map[string]string{
+		"home/${aws:username}/*":          "home/alice/*",
+		"home/${AWS:UserName}":            "home/alice",
+		"home/${aws:userid, 'anonymous'}": "home/anonymous",
+		"literal-${*}-${?}-${$}":          "literal-*-?-$",
+		"no variables":                    "no variables",
+	}
+
+	for s, expected := range tests {
+		substituted, ok := SubstitutePolicyVariables(s, context)
+		if !ok || substituted != expected {
+			t.Errorf("%s: Expected: %s, got: %s (%v)", s, expected, substituted, ok)
+		}
+	}
+}
+
+func TestPolicyVariable_SubstituteWhenMissing(t *testing.T) {
+	if _, ok := SubstitutePolicyVariables("home/${aws:username}", RequestContext{}); ok {
+		t.Errorf("Expected substitution to fail without a value and a default")
+	}
+}
+
+func TestARN_ParseWithPolicyVariables(t *testing.T) {
+	expected := ARN{
+		Partition: "aws", Service: "iam", Account: "${aws:PrincipalAccount}",
+		Resource: "user/${aws:username}", ResourceType: "user", ResourceId: "${aws:username}",
+	}
+
+	arn, err := ParseARN("arn:aws:iam::${aws:PrincipalAccount}:user/${aws:username}")
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if !reflect.DeepEqual(arn, expected) {
+		t.Errorf("Expected: %+v, got: %+v", expected, arn)
+	}
+	if arn.Breadth() != BreadthExact {
+		t.Errorf("Expected: %v, got: %v", BreadthExact, arn.Breadth())
+	}
+}
+
+func TestARN_MatchesInContext(t *testing.T) {
+	arn, _ := ParseARN("arn:aws:s3:::home/${aws:username}/*")
+	context := RequestContext{"aws:username": {"alice"}}
+
+	if !arn.MatchesInContext("arn:aws:s3:::home/alice/notes.txt", context) {
+		t.Errorf("Expected the resource of alice to match")
+	}
+	if arn.MatchesInContext("arn:aws:s3:::home/bob/notes.txt", context) {
+		t.Errorf("Expected the resource of bob not to match")
+	}
+	if arn.Matches("arn:aws:s3:::home/alice/notes.txt") {
+		t.Errorf("Expected no match without a request context")
+	}
+}
+
+func TestARN_MatchesInContextDoesNotExpandWildcardsInValues(t *testing.T) {
+	arn, _ := ParseARN("arn:aws:s3:::home/${aws:username}/${*}")
+	context := RequestContext{"aws:username": {"*"}}
+
+	if arn.MatchesInContext("arn:aws:s3:::home/alice/*", context) {
+		t.Errorf("Expected a substituted \"*\" to match only literally")
+	}
+	if !arn.MatchesInContext("arn:aws:s3:::home/*/*", context) {
+		t.Errorf("Expected a substituted \"*\" and the ${*} escape to match a literal \"*\"")
+	}
+}
+
+func TestStatement_MatchesResourceInContext(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::home/${aws:username}/*"}`)
+	var stat Statement
+	if err := stat.UnmarshalJSON(data); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+	context := RequestContext{"aws:username": {"alice"}}
+
+	if !stat.MatchesResourceInContext("arn:aws:s3:::home/alice/notes.txt", context) {
+		t.Errorf("Expected the resource of alice to match")
+	}
+	if stat.MatchesResourceInContext("arn:aws:s3:::home/bob/notes.txt", context) {
+		t.Errorf("Expected the resource of bob not to match")
+	}
+	if stat.MatchesResource("arn:aws:s3:::home/alice/notes.txt") {
+		t.Errorf("Expected no match without a request context")
+	}
+}
+
+func TestStatement_PolicyVariables(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":"s3:ListBucket","Resource":"arn:aws:s3:::bucket","Condition":{"StringLike":{"s3:prefix":["home/${aws:username}/*"]}}}`)
+	var stat Statement
+	err := json.Unmarshal(data, &stat)
+	expected := []PolicyVariable{{Name: "aws:username"}}
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if !reflect.DeepEqual(stat.PolicyVariables(), expected) {
+		t.Errorf("Expected: %v, got: %v", expected, stat.PolicyVariables())
+	}
+}
+
+func TestStatement_UnmarshalMalformedPolicyVariable(t *testing.T) {
+	tests := []string{
+		`{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::home/${username}/*"}`,
+		`{"Effect":"Allow","Action":"s3:GetObject","Resource":"*","Condition":{"StringEquals":{"s3:prefix":"${aws:username"}}}`,
+	}
+
+	for _, data := range tests {
+		var stat Statement
+		if err := json.Unmarshal([]byte(data), &stat); err == nil || !strings.Contains(err.Error(), "policy variable") {
+			t.Errorf("%s: Expected a policy variable error, got: %v", data, err)
+		}
+	}
+}
+
+func TestPolicyDocument_UnmarshalJSONPolicyVariablesInOldVersion(t *testing.T) {
+	data := []byte(`{"Version":"2008-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::home/${aws:username}/*"}]}`)
+	var pd PolicyDocument
+	err := json.Unmarshal(data, &pd)
+	expected := `/Statement/0: policy variables are not supported in Version 2008-10-17, found "${aws:username}"`
+
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error: %s, got: %v", expected, err)
+	}
+}
+
+func TestPolicyDocument_UnmarshalJSONPolicyVariablesWithoutVersion(t *testing.T) {
+	data := []byte(`{"Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"arn:aws:s3:::home/${aws:username}/*"}]}`)
+	var pd PolicyDocument
+	err := json.Unmarshal(data, &pd)
+	expected := `/Statement/0: policy variables are not supported without a Version (which defaults to 2008-10-17), found "${aws:username}"`
+
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error: %s, got: %v", expected, err)
+	}
+}
+
+func TestPolicyDocument_IsAllowedWithPolicyVariables(t *testing.T) {
+	pd := policyDocumentOf(t, `{"Version":"2012-10-17","Statement":[
+		{"Effect":"Allow","Action":"s3:ListBucket","Resource":"arn:aws:s3:::bucket","Condition":{"StringLike":{"s3:prefix":["home/${aws:username}/*"]}}},
+		{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::bucket/home/${aws:username}/*"}
+	]}`)
+	alice := RequestContext{"aws:username": {"alice"}, "s3:prefix": {"home/alice/docs"}}
+	bob := RequestContext{"aws:username": {"bob"}, "s3:prefix": {"home/alice/docs"}}
+
+	if decision := pd.IsAllowed("", "s3:GetObject", "arn:aws:s3:::bucket/home/alice/key", alice).Decision; decision != DecisionAllow {
+		t.Errorf("Expected: %v, got: %v", DecisionAllow, decision)
+	}
+	if decision := pd.IsAllowed("", "s3:GetObject", "arn:aws:s3:::bucket/home/alice/key", bob).Decision; decision != DecisionImplicitDeny {
+		t.Errorf("Expected: %v, got: %v", DecisionImplicitDeny, decision)
+	}
+	if decision := pd.IsAllowed("", "s3:ListBucket", "arn:aws:s3:::bucket", alice).Decision; decision != DecisionAllow {
+		t.Errorf("Expected: %v, got: %v", DecisionAllow, decision)
+	}
+	if decision := pd.IsAllowed("", "s3:ListBucket", "arn:aws:s3:::bucket", bob).Decision; decision != DecisionImplicitDeny {
+		t.Errorf("Expected: %v, got: %v", DecisionImplicitDeny, decision)
+	}
+}
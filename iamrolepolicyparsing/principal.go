@@ -0,0 +1,239 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/**
+ * Principal struct represents the Principal or NotPrincipal block of a statement.
+ *
+ * Wildcard is true if the block was "*" or if the "AWS" list contains "*" (the two forms are equivalent).
+ * NotPrincipal is true if the block came from the "NotPrincipal" key.
+ * AWS, Federated, Service and CanonicalUser hold the principal id strings listed under the matching keys.
+ *
+ * for grammar see https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_grammar.html
+ */
+type Principal struct {
+	Wildcard      bool
+	NotPrincipal  bool
+	AWS           []string
+	Federated     []string
+	Service       []string
+	CanonicalUser []string
+}
+
+func (this *Principal) String() string {
+	return fmt.Sprintf(
+		"Principal{Wildcard: %v, NotPrincipal: %v, AWS: %v, Federated: %v, Service: %v, CanonicalUser: %v}",
+		this.Wildcard,
+		this.NotPrincipal,
+		this.AWS,
+		this.Federated,
+		this.Service,
+		this.CanonicalUser,
+	)
+}
+
+/**
+ * Returns the account IDs named by the "AWS" principals, in order of appearance and without duplicates.
+ *
+ * Both the bare 12-digit form ("123456789012") and the account segment of IAM/STS ARNs
+ * ("arn:aws:iam::123456789012:role/Admin") are recognized. "*" is not an account ID and is skipped.
+ */
+func (principal *Principal) AccountIDs() []string {
+	var accountIds []string
+	for _, id := range principal.AWS {
+		accountId := id
+		if arn, err := ParseARN(id); err == nil {
+			accountId = arn.Account
+		}
+		if isAccountId(accountId) && !containsString(accountIds, accountId) {
+			accountIds = append(accountIds, accountId)
+		}
+	}
+	return accountIds
+}
+
+/**
+ * Returns the IAM role ARNs named by the "AWS" principals, in order of appearance and without duplicates.
+ */
+func (principal *Principal) RoleARNs() []string {
+	var roles []string
+	for _, id := range principal.AWS {
+		arn, err := ParseARN(id)
+		if err != nil || arn.Service != "iam" {
+			continue
+		}
+		if arn.ResourceType == "role" && !containsString(roles, id) {
+			roles = append(roles, id)
+		}
+	}
+	return roles
+}
+
+/**
+ * Returns the service principals (e.g. "ec2.amazonaws.com"), in order of appearance and without duplicates.
+ */
+func (principal *Principal) Services() []string {
+	var services []string
+	for _, service := range principal.Service {
+		if !containsString(services, service) {
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+/**
+ * Returns whether the principal block names the given principal, taking NotPrincipal into account.
+ *
+ * The principal is a principal id string such as "arn:aws:iam::123456789012:role/Admin", "123456789012",
+ * "ec2.amazonaws.com" or a canonical user id. An account entry ("123456789012" or "arn:aws:iam::123456789012:root")
+ * names every principal of that account.
+ */
+func (principal *Principal) Matches(principalId string) bool {
+	return principal.names(principalId) != principal.NotPrincipal
+}
+
+func (principal *Principal) names(principalId string) bool {
+	if principal.Wildcard {
+		return true
+	}
+	if containsString(principal.Federated, principalId) ||
+		containsString(principal.Service, principalId) ||
+		containsString(principal.CanonicalUser, principalId) {
+		return true
+	}
+
+	principalAccount := principalId
+	if arn, err := ParseARN(principalId); err == nil {
+		principalAccount = arn.Account
+	}
+	for _, id := range principal.AWS {
+		if id == principalId {
+			return true
+		}
+		account := id
+		if arn, err := ParseARN(id); err == nil {
+			if arn.Resource != "root" {
+				continue
+			}
+			account = arn.Account
+		}
+		if isAccountId(account) && account == principalAccount {
+			return true
+		}
+	}
+	return false
+}
+
+// The identity providers that can be named by their domain in a "Federated" principal
+var wellKnownIdentityProviders = []string{"cognito-identity.amazonaws.com", "www.amazon.com", "graph.facebook.com", "accounts.google.com"}
+
+/**
+ * Validates a principal id listed under the given key of a principal map:
+ *
+ *   AWS           - "*", a 12-digit account ID or an IAM/STS ARN of an account root, user, role, assumed role or federated user
+ *   Service       - a service principal such as "ec2.amazonaws.com"
+ *   Federated     - a SAML or OIDC provider ARN or a well-known identity provider such as "accounts.google.com"
+ *   CanonicalUser - a 64-character hexadecimal canonical user ID
+ */
+func validatePrincipalId(key string, id string) error {
+	switch key {
+	case "AWS":
+		if id == "*" || isAccountId(id) || isPrincipalARN(id) {
+			return nil
+		}
+		return errors.New(fmt.Sprintf(`principal "%s" should be "*", a 12-digit account ID or an IAM ARN such as "arn:aws:iam::123456789012:role/Name"`, id))
+	case "Service":
+		if isServicePrincipal(id) {
+			return nil
+		}
+		return errors.New(fmt.Sprintf(`service principal "%s" should be a service name such as "ec2.amazonaws.com"`, id))
+	case "Federated":
+		if containsString(wellKnownIdentityProviders, id) || isIdentityProviderARN(id) {
+			return nil
+		}
+		return errors.New(fmt.Sprintf(`federated principal "%s" should be a SAML or OIDC provider ARN or one of "%s"`, id, strings.Join(wellKnownIdentityProviders, `", "`)))
+	case "CanonicalUser":
+		if isCanonicalUserId(id) {
+			return nil
+		}
+		return errors.New(fmt.Sprintf(`canonical user "%s" should be a 64-character hexadecimal ID`, id))
+	}
+	return nil
+}
+
+// IAM and STS ARNs naming an account root, a user, a role, an assumed role or a federated user, without wildcards
+func isPrincipalARN(id string) bool {
+	arn, err := ParseARN(id)
+	if err != nil || arn.HasWildcard() || arn.Region != "" || !isAccountId(arn.Account) || arn.ResourceId == "" {
+		return false
+	}
+	switch arn.Service {
+	case "iam":
+		return arn.Resource == "root" || arn.ResourceType == "user" || arn.ResourceType == "role"
+	case "sts":
+		return arn.ResourceType == "assumed-role" || arn.ResourceType == "federated-user"
+	}
+	return false
+}
+
+func isIdentityProviderARN(id string) bool {
+	arn, err := ParseARN(id)
+	return err == nil && !arn.HasWildcard() && arn.Service == "iam" && arn.Region == "" && isAccountId(arn.Account) &&
+		(arn.ResourceType == "saml-provider" || arn.ResourceType == "oidc-provider") && arn.ResourceId != ""
+}
+
+// Service principals are dot-separated labels of lowercase letters, digits and hyphens ending in amazonaws.com
+// (or amazonaws.com.cn in the China regions), e.g. "ec2.amazonaws.com" or "logs.us-east-1.amazonaws.com"
+func isServicePrincipal(id string) bool {
+	name, found := strings.CutSuffix(id, ".amazonaws.com")
+	if !found {
+		name, found = strings.CutSuffix(id, ".amazonaws.com.cn")
+	}
+	if !found {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || !isServicePrefix(label) {
+			return false
+		}
+	}
+	return true
+}
+
+func isCanonicalUserId(id string) bool {
+	if len(id) != 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAccountId(s string) bool {
+	if len(s) != 12 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(slice []string, s string) bool {
+	for _, element := range slice {
+		if element == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPrincipal_UnmarshalWildcard(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::example-bucket/*"}`)
+	var stat Statement
+	err := stat.UnmarshalJSON(data)
+
+	expected := &Principal{Wildcard: true}
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if !reflect.DeepEqual(stat.Principals, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, stat.Principals)
+	}
+}
+
+func TestPrincipal_UnmarshalMap(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Principal":{"AWS":["123456789012","arn:aws:iam::210987654321:role/Admin"],"Service":["ec2.amazonaws.com"],"Federated":["cognito-identity.amazonaws.com"],"CanonicalUser":["79a59df900b949e55d96a1e698fbacedfd6e09d98eacf8f8d5218e7cd47ef2be"]},"Action":"sts:AssumeRole","Resource":"*"}`)
+	var stat Statement
+	err := stat.UnmarshalJSON(data)
+
+	expected := &Principal{
+		AWS:           []string{"123456789012", "arn:aws:iam::210987654321:role/Admin"},
+		Federated:     []string{"cognito-identity.amazonaws.com"},
+		Service:       []string{"ec2.amazonaws.com"},
+		CanonicalUser: []string{"79a59df900b949e55d96a1e698fbacedfd6e09d98eacf8f8d5218e7cd47ef2be"},
+	}
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if !reflect.DeepEqual(stat.Principals, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, stat.Principals)
+	}
+}
+
+func TestPrincipal_UnmarshalNotPrincipal(t *testing.T) {
+	data := []byte(`{"Effect":"Deny","NotPrincipal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"Action":"s3:*","Resource":"*"}`)
+	var stat Statement
+	err := stat.UnmarshalJSON(data)
+
+	expected := &Principal{NotPrincipal: true, AWS: []string{"arn:aws:iam::123456789012:user/JohnDoe"}}
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if !reflect.DeepEqual(stat.Principals, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, stat.Principals)
+	}
+}
+
+func TestPrincipal_UnmarshalAWSWildcard(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Principal":{"AWS":["*"]},"Action":"s3:GetObject","Resource":"*"}`)
+	var stat Statement
+	err := stat.UnmarshalJSON(data)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if stat.Principals == nil || !stat.Principals.Wildcard {
+		t.Errorf("Expected a wildcard principal, got: %v", stat.Principals)
+	}
+}
+
+func TestPrincipal_UnmarshalWhenAbsent(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}`)
+	var stat Statement
+	err := stat.UnmarshalJSON(data)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if stat.Principals != nil {
+		t.Errorf("Expected: <nil>, got: %v", stat.Principals)
+	}
+}
+
+func TestPrincipal_AccountIDs(t *testing.T) {
+	principal := Principal{AWS: []string{
+		"123456789012",
+		"arn:aws:iam::210987654321:role/Admin",
+		"arn:aws:iam::123456789012:user/JohnDoe",
+		"arn:aws:sts::333333333333:assumed-role/Admin/session",
+		"*",
+	}}
+	expected := []string{"123456789012", "210987654321", "333333333333"}
+
+	if !reflect.DeepEqual(principal.AccountIDs(), expected) {
+		t.Errorf("Expected: %v, got: %v", expected, principal.AccountIDs())
+	}
+}
+
+func TestPrincipal_RoleARNs(t *testing.T) {
+	principal := Principal{AWS: []string{
+		"123456789012",
+		"arn:aws:iam::210987654321:role/Admin",
+		"arn:aws:iam::123456789012:user/JohnDoe",
+		"arn:aws:iam::210987654321:role/Admin",
+		"arn:aws:iam::210987654321:role/path/ReadOnly",
+	}}
+	expected := []string{"arn:aws:iam::210987654321:role/Admin", "arn:aws:iam::210987654321:role/path/ReadOnly"}
+
+	if !reflect.DeepEqual(principal.RoleARNs(), expected) {
+		t.Errorf("Expected: %v, got: %v", expected, principal.RoleARNs())
+	}
+}
+
+func TestPrincipal_Services(t *testing.T) {
+	principal := Principal{Service: []string{"ec2.amazonaws.com", "lambda.amazonaws.com", "ec2.amazonaws.com"}}
+	expected := []string{"ec2.amazonaws.com", "lambda.amazonaws.com"}
+
+	if !reflect.DeepEqual(principal.Services(), expected) {
+		t.Errorf("Expected: %v, got: %v", expected, principal.Services())
+	}
+}
+
+func TestPrincipal_AccessorsWhenEmpty(t *testing.T) {
+	principal := Principal{Wildcard: true}
+
+	if principal.AccountIDs() != nil || principal.RoleARNs() != nil || principal.Services() != nil {
+		t.Errorf("Expected no account IDs, roles and services for a wildcard principal")
+	}
+}
+
+func TestPrincipal_Matches(t *testing.T) {
+	principal := Principal{
+		AWS:     []string{"arn:aws:iam::123456789012:root", "210987654321", "arn:aws:iam::333333333333:role/Admin"},
+		Service: []string{"ec2.amazonaws.com"},
+	}
+	tests := map[string]bool{
+		"arn:aws:iam::123456789012:role/Anything":         true,
+		"arn:aws:sts::210987654321:assumed-role/Admin/me": true,
+		"arn:aws:iam::333333333333:role/Admin":            true,
+		"arn:aws:iam::333333333333:role/Other":            false,
+		"ec2.amazonaws.com":                               true,
+		"lambda.amazonaws.com":                            false,
+		"123456789012":                                    true,
+	}
+
+	for principalId, expected := range tests {
+		if principal.Matches(principalId) != expected {
+			t.Errorf("%s: Expected: %v, got: %v", principalId, expected, !expected)
+		}
+	}
+}
+
+func TestPrincipal_MatchesWhenNotPrincipal(t *testing.T) {
+	principal := Principal{NotPrincipal: true, AWS: []string{"arn:aws:iam::123456789012:role/Admin"}}
+
+	if principal.Matches("arn:aws:iam::123456789012:role/Admin") {
+		t.Errorf("Expected: false, got: true")
+	}
+	if !principal.Matches("arn:aws:iam::123456789012:role/Other") {
+		t.Errorf("Expected: true, got: false")
+	}
+}
+
+func TestPrincipal_MatchesWhenWildcard(t *testing.T) {
+	principal := Principal{Wildcard: true}
+
+	if !principal.Matches("arn:aws:iam::123456789012:role/Admin") {
+		t.Errorf("Expected: true, got: false")
+	}
+}
+
+func TestPrincipal_ValidatePrincipalId(t *testing.T) {
+	valid := map[string][]string{
+		"AWS": {
+			"*",
+			"123456789012",
+			"arn:aws:iam::123456789012:root",
+			"arn:aws:iam::123456789012:user/path/JohnDoe",
+			"arn:aws-cn:iam::123456789012:role/Admin",
+			"arn:aws:sts::123456789012:assumed-role/Admin/session",
+			"arn:aws:sts::123456789012:federated-user/JohnDoe",
+		},
+		"Service":       {"ec2.amazonaws.com", "logs.us-east-1.amazonaws.com", "ec2.amazonaws.com.cn"},
+		"Federated":     {"cognito-identity.amazonaws.com", "accounts.google.com", "arn:aws:iam::123456789012:saml-provider/Okta", "arn:aws:iam::123456789012:oidc-provider/token.actions.githubusercontent.com"},
+		"CanonicalUser": {"79a59df900b949e55d96a1e698fbacedfd6e09d98eacf8f8d5218e7cd47ef2be"},
+	}
+
+	for key, ids := range valid {
+		for _, id := range ids {
+			if err := validatePrincipalId(key, id); err != nil {
+				t.Errorf("%s %s: Expected error: <nil>, got: %v", key, id, err)
+			}
+		}
+	}
+}
+
+func TestPrincipal_ValidatePrincipalIdWhenInvalid(t *testing.T) {
+	invalid := map[string][]string{
+		"AWS": {
+			"12345",
+			"JohnDoe",
+			"arn:aws:iam::123456789012:role/*",
+			"arn:aws:iam::123456789012:group/Admins",
+			"arn:aws:s3:::bucket",
+			"arn:aws:iam::*:root",
+		},
+		"Service":       {"ec2", "EC2.amazonaws.com", "ec2..amazonaws.com", "*.amazonaws.com", "ec2.example.com"},
+		"Federated":     {"example.com", "arn:aws:iam::123456789012:role/Admin", "arn:aws:iam::123456789012:saml-provider/"},
+		"CanonicalUser": {"79a59df9", "79A59DF900B949E55D96A1E698FBACEDFD6E09D98EACF8F8D5218E7CD47EF2BE", "z9a59df900b949e55d96a1e698fbacedfd6e09d98eacf8f8d5218e7cd47ef2be"},
+	}
+
+	for key, ids := range invalid {
+		for _, id := range ids {
+			if err := validatePrincipalId(key, id); err == nil {
+				t.Errorf("%s %s: Expected an error, got: <nil>", key, id)
+			}
+		}
+	}
+}
+
+func TestStatement_UnmarshalInvalidPrincipalId(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Principal":{"AWS":["123456789012","JohnDoe"]},"Action":"s3:GetObject","Resource":"*"}`)
+	var stat Statement
+	message := `principal "JohnDoe" should be "*", a 12-digit account ID or an IAM ARN such as "arn:aws:iam::123456789012:role/Name"`
+	expectedErr := &ParseError{Code: CodeInvalidPrincipal, Path: "/Principal/AWS/1", Message: message, Err: errors.New(message), Line: 1, Column: 54}
+
+	err := stat.UnmarshalJSON(data)
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %+v, got: %+v", expectedErr, err)
+	}
+}
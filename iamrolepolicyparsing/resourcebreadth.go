@@ -0,0 +1,107 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/**
+ * ResourceBreadth classifies how many resources a Resource value can match, from the narrowest to the broadest.
+ *
+ *   BreadthExact          - no wildcards, e.g. "arn:aws:s3:::bucket/key"
+ *   BreadthPrefixWildcard - wildcards after a concrete resource prefix, e.g. "arn:aws:s3:::bucket/*" or "arn:aws:iam::123456789012:role/app-*"
+ *   BreadthAccountWide    - every resource of a type in a single account, e.g. "arn:aws:dynamodb:us-east-1:123456789012:table/*"
+ *   BreadthServiceWide    - every resource of a service across accounts, e.g. "arn:aws:s3:::*", "arn:aws:iam::*:role/*" or "arn:aws:dynamodb:*:*:table/*"
+ *   BreadthGlobal         - every resource, e.g. "*" or "arn:aws:*:*:*:*"
+ */
+type ResourceBreadth int
+
+const (
+	BreadthExact ResourceBreadth = iota
+	BreadthPrefixWildcard
+	BreadthAccountWide
+	BreadthServiceWide
+	BreadthGlobal
+)
+
+var resourceBreadthNames = []string{"exact", "prefix-wildcard", "account-wide", "service-wide", "global"}
+
+func (breadth ResourceBreadth) String() string {
+	if breadth < BreadthExact || breadth > BreadthGlobal {
+		return fmt.Sprintf("ResourceBreadth(%d)", int(breadth))
+	}
+	return resourceBreadthNames[breadth]
+}
+
+/**
+ * Parses the name of a ResourceBreadth as returned by its String method (e.g. "service-wide").
+ */
+func ParseResourceBreadth(name string) (ResourceBreadth, error) {
+	for i, breadthName := range resourceBreadthNames {
+		if breadthName == name {
+			return ResourceBreadth(i), nil
+		}
+	}
+	return BreadthExact, errors.New(fmt.Sprintf(`resource breadth should be one of the following: "%s"`, strings.Join(resourceBreadthNames, `", "`)))
+}
+
+/**
+ * Classifies a single Resource value.
+ *
+ * Values that are not valid ARNs are classified only by whether they contain a wildcard.
+ */
+func ClassifyResource(resource string) ResourceBreadth {
+	arn, err := ParseARN(resource)
+	if err != nil {
+		if !containsWildcard(resource) {
+			return BreadthExact
+		}
+		if isOnlyWildcards(resource) {
+			return BreadthGlobal
+		}
+		return BreadthPrefixWildcard
+	}
+	return arn.Breadth()
+}
+
+/**
+ * Classifies the ARN (see ResourceBreadth).
+ */
+func (arn ARN) Breadth() ResourceBreadth {
+	arn = arn.withoutPolicyVariables()
+	if !arn.HasWildcard() {
+		return BreadthExact
+	}
+	if containsWildcard(arn.Service) {
+		return BreadthGlobal
+	}
+	if !arn.isResourceFullyWild() {
+		return BreadthPrefixWildcard
+	}
+	if containsWildcard(arn.Account) || (arn.Account == "" && arn.Region == "") {
+		return BreadthServiceWide
+	}
+	return BreadthAccountWide
+}
+
+// The resource is fully wild if a wildcard follows right at its beginning or right after the resource type,
+// e.g. "*", "table/*" or "function:*". S3 bucket/object ARNs have no resource type, so "bucket/*" is not fully wild.
+func (arn ARN) isResourceFullyWild() bool {
+	wildcardIndex := strings.IndexAny(arn.Resource, "*?")
+	if wildcardIndex == -1 {
+		return false
+	}
+	if wildcardIndex == 0 {
+		return true
+	}
+	return arn.ResourceType != "" && wildcardIndex == len(arn.ResourceType)+1
+}
+
+func containsWildcard(s string) bool {
+	return strings.ContainsAny(s, "*?")
+}
+
+func isOnlyWildcards(s string) bool {
+	return strings.Trim(s, "*?") == ""
+}
@@ -0,0 +1,91 @@
+package iamrolepolicyparsing
+
+import (
+	"testing"
+)
+
+func TestClassifyResource(t *testing.T) {
+	tests := map[string]ResourceBreadth{
+		"arn:aws:s3:::bucket/key":                              BreadthExact,
+		"arn:aws:iam::123456789012:role/Admin":                 BreadthExact,
+		"lol":                                                  BreadthExact,
+		"arn:aws:s3:::bucket/*":                                BreadthPrefixWildcard,
+		"arn:aws:s3:::bucket*":                                 BreadthPrefixWildcard,
+		"arn:aws:iam::123456789012:role/app-*":                 BreadthPrefixWildcard,
+		"arn:aws:iam::123456789012:role/path/*":                BreadthPrefixWildcard,
+		"arn:aws:sqs:*:123456789012:queue":                     BreadthPrefixWildcard,
+		"arn:aws:iam::*:role/Admin":                            BreadthPrefixWildcard,
+		"arn:aws:dynamodb:us-east-1:123456789012:table/*":      BreadthAccountWide,
+		"arn:aws:iam::123456789012:role/*":                     BreadthAccountWide,
+		"arn:aws:lambda:*:123456789012:function:*":             BreadthAccountWide,
+		"arn:aws:s3:::*":                                       BreadthServiceWide,
+		"arn:aws:s3:::*/*":                                     BreadthServiceWide,
+		"arn:aws:dynamodb:*:*:table/*":                         BreadthServiceWide,
+		"arn:aws:iam::*:role/*":                                BreadthServiceWide,
+		"arn:aws:ec2:us-east-1:*:instance/*":                   BreadthServiceWide,
+		"*":                                                    BreadthGlobal,
+		"arn:aws:*:*:*:*":                                      BreadthGlobal,
+		"arn:aws:*:us-east-1:123456789012:thing/*":             BreadthGlobal,
+		"arn:aws:kinesis:us-east-1:123456789012:stream/name-?": BreadthPrefixWildcard,
+	}
+
+	for resource, expected := range tests {
+		if breadth := ClassifyResource(resource); breadth != expected {
+			t.Errorf("%s: Expected: %v, got: %v", resource, expected, breadth)
+		}
+	}
+}
+
+func TestResourceBreadth_String(t *testing.T) {
+	if BreadthServiceWide.String() != "service-wide" {
+		t.Errorf("Expected: service-wide, got: %s", BreadthServiceWide.String())
+	}
+	if ResourceBreadth(42).String() != "ResourceBreadth(42)" {
+		t.Errorf("Expected: ResourceBreadth(42), got: %s", ResourceBreadth(42).String())
+	}
+}
+
+func TestParseResourceBreadth(t *testing.T) {
+	for breadth := BreadthExact; breadth <= BreadthGlobal; breadth++ {
+		parsed, err := ParseResourceBreadth(breadth.String())
+		if err != nil || parsed != breadth {
+			t.Errorf("Expected: %v, got: %v (error: %v)", breadth, parsed, err)
+		}
+	}
+}
+
+func TestParseResourceBreadthWhenUnknown(t *testing.T) {
+	expectedErr := `resource breadth should be one of the following: "exact", "prefix-wildcard", "account-wide", "service-wide", "global"`
+
+	_, err := ParseResourceBreadth("huge")
+
+	if err == nil || err.Error() != expectedErr {
+		t.Errorf("Expected error: %s, got: %v", expectedErr, err)
+	}
+}
+
+func TestStatement_ResourceBreadth(t *testing.T) {
+	stat := Statement{
+		Effect:        stringOf("Allow"),
+		Action:        true,
+		Resource:      true,
+		ActionValue:   "s3:GetObject",
+		ResourceValue: []interface{}{"arn:aws:s3:::bucket/key", "arn:aws:iam::123456789012:role/*", "arn:aws:s3:::bucket/*"},
+	}
+	if stat.ResourceBreadth() != BreadthAccountWide {
+		t.Errorf("Expected: %v, got: %v", BreadthAccountWide, stat.ResourceBreadth())
+	}
+}
+
+func TestStatement_ResourceBreadthWhenNotResource(t *testing.T) {
+	stat := Statement{
+		Effect:        stringOf("Allow"),
+		Action:        true,
+		Resource:      false,
+		ActionValue:   "s3:GetObject",
+		ResourceValue: "arn:aws:s3:::bucket/key",
+	}
+	if stat.ResourceBreadth() != BreadthGlobal {
+		t.Errorf("Expected: %v, got: %v", BreadthGlobal, stat.ResourceBreadth())
+	}
+}
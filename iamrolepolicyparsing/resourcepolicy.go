@@ -0,0 +1,286 @@
+package iamrolepolicyparsing
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+/**
+ * ResourcePolicy struct represents a resource-based policy given as a bare policy document, e.g. an ECR repository
+ * policy or a Lambda function policy. Every statement has to name a principal (see policykind.go).
+ *
+ * The resource types with their own rules have their own types: BucketPolicy, QueuePolicy, TopicPolicy and KeyPolicy.
+ *
+ * for grammar see https://docs.aws.amazon.com/IAM/latest/UserGuide/access_policies_identity-vs-resource.html
+ */
+type ResourcePolicy struct {
+	PolicyDocument *PolicyDocument
+}
+
+func (this ResourcePolicy) String() string {
+	return "ResourcePolicy{PolicyDocument: " + this.PolicyDocument.String() + "}"
+}
+
+/**
+ * Parses a resource policy, a policy document such as {"Version": "2012-10-17", "Statement": [...]}.
+ * Only the first error is returned, see Validate for getting all of them.
+ */
+func (policy *ResourcePolicy) UnmarshalJSON(data []byte) error {
+	return firstError(checkSource(data, policy.unmarshal(data)))
+}
+
+/**
+ * Returns every error of the resource policy instead of only the first one, e.g. all the statements that don't name
+ * a principal. Returns nil if the policy is valid.
+ */
+func (policy *ResourcePolicy) Validate(data []byte) []*ParseError {
+	return toParseErrors(checkSource(data, policy.unmarshal(data)))
+}
+
+func (policy *ResourcePolicy) unmarshal(data []byte) []error {
+	return unmarshalDocument(data, &policy.PolicyDocument, PolicyKindResource, nil)
+}
+
+/**
+ * Writes the resource policy back as a bare policy document, the form resource-based policies are attached in.
+ */
+func (policy ResourcePolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(policy.PolicyDocument)
+}
+
+/**
+ * KeyPolicy struct represents the key policy of a KMS key (the KeyPolicy of an AWS::KMS::Key), a bare policy document.
+ *
+ * On top of the rules of ResourcePolicy, Resource has to be "*", which stands for the key the policy is attached to.
+ *
+ * for grammar see https://docs.aws.amazon.com/kms/latest/developerguide/key-policy-overview.html
+ */
+type KeyPolicy struct {
+	PolicyDocument *PolicyDocument
+}
+
+func (this KeyPolicy) String() string {
+	return "KeyPolicy{PolicyDocument: " + this.PolicyDocument.String() + "}"
+}
+
+/**
+ * Parses the KeyPolicy property of an AWS::KMS::Key, a policy document whose resources can only be "*".
+ * Only the first error is returned, see Validate for getting all of them.
+ */
+func (policy *KeyPolicy) UnmarshalJSON(data []byte) error {
+	return firstError(checkSource(data, policy.unmarshal(data)))
+}
+
+/**
+ * Returns every error of the key policy instead of only the first one, e.g. each Resource value naming a key ARN
+ * instead of "*". Returns nil if the policy is valid.
+ */
+func (policy *KeyPolicy) Validate(data []byte) []*ParseError {
+	return toParseErrors(checkSource(data, policy.unmarshal(data)))
+}
+
+func (policy *KeyPolicy) unmarshal(data []byte) []error {
+	return unmarshalDocument(data, &policy.PolicyDocument, PolicyKindResource, func(pd *PolicyDocument) []error {
+		return pd.resourceErrors("key policy", `"*"`, func(arn ARN) bool { return arn == WildcardARN })
+	})
+}
+
+/**
+ * Writes the key policy back as the value of the KeyPolicy property.
+ */
+func (policy KeyPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(policy.PolicyDocument)
+}
+
+/**
+ * BucketPolicy struct represents the properties of an AWS::S3::BucketPolicy: the bucket and its policy document.
+ *
+ * On top of the rules of ResourcePolicy, the resources have to be S3 ARNs (the bucket or its objects) or "*".
+ *
+ * for grammar see https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/aws-resource-s3-bucketpolicy.html
+ */
+type BucketPolicy struct {
+	Bucket         *string         `json:"Bucket"`
+	PolicyDocument *PolicyDocument `json:"PolicyDocument"`
+}
+
+func (this BucketPolicy) String() string {
+	return fmt.Sprintf("BucketPolicy{Bucket: %s, PolicyDocument: %s}", stringPtrString(this.Bucket), this.PolicyDocument.String())
+}
+
+/**
+ * Parses a {"Bucket": ..., "PolicyDocument": ...} object.
+ * Only the first error is returned, see Validate for getting all of them.
+ */
+func (policy *BucketPolicy) UnmarshalJSON(data []byte) error {
+	return firstError(checkSource(data, policy.unmarshal(data)))
+}
+
+/**
+ * Returns every error of the bucket policy instead of only the first one, with paths from the envelope
+ * (e.g. "/PolicyDocument/Statement/0/Resource" for a resource outside of S3). Returns nil if the policy is valid.
+ */
+func (policy *BucketPolicy) Validate(data []byte) []*ParseError {
+	return toParseErrors(checkSource(data, policy.unmarshal(data)))
+}
+
+func (policy *BucketPolicy) unmarshal(data []byte) []error {
+	resourceErrors := serviceResourceErrors("bucket policy", "s3")
+	return unmarshalEnvelope(data, &policy.PolicyDocument, PolicyKindResource, resourceErrors, envelopeField{key: "Bucket", target: &policy.Bucket, required: true})
+}
+
+/**
+ * Writes the policy back as a {"Bucket": ..., "PolicyDocument": ...} object.
+ * Absent values are omitted.
+ */
+func (policy BucketPolicy) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		Bucket         *string         `json:"Bucket,omitempty"`
+		PolicyDocument *PolicyDocument `json:"PolicyDocument,omitempty"`
+	}{
+		Bucket:         policy.Bucket,
+		PolicyDocument: policy.PolicyDocument,
+	}
+	return json.Marshal(aux)
+}
+
+/**
+ * QueuePolicy struct represents the properties of an AWS::SQS::QueuePolicy: the queue URLs and their policy document.
+ *
+ * On top of the rules of ResourcePolicy, the resources have to be SQS ARNs or "*".
+ *
+ * for grammar see https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/aws-resource-sqs-queuepolicy.html
+ */
+type QueuePolicy struct {
+	Queues         []string        `json:"Queues"`
+	PolicyDocument *PolicyDocument `json:"PolicyDocument"`
+}
+
+func (this QueuePolicy) String() string {
+	return fmt.Sprintf("QueuePolicy{Queues: %v, PolicyDocument: %s}", this.Queues, this.PolicyDocument.String())
+}
+
+/**
+ * Parses a {"Queues": [...], "PolicyDocument": ...} object.
+ * Only the first error is returned, see Validate for getting all of them.
+ */
+func (policy *QueuePolicy) UnmarshalJSON(data []byte) error {
+	return firstError(checkSource(data, policy.unmarshal(data)))
+}
+
+/**
+ * Returns every error of the queue policy instead of only the first one, e.g. a missing Queues key along with
+ * the resources of the document that are not SQS ARNs. Returns nil if the policy is valid.
+ */
+func (policy *QueuePolicy) Validate(data []byte) []*ParseError {
+	return toParseErrors(checkSource(data, policy.unmarshal(data)))
+}
+
+func (policy *QueuePolicy) unmarshal(data []byte) []error {
+	resourceErrors := serviceResourceErrors("queue policy", "sqs")
+	return unmarshalEnvelope(data, &policy.PolicyDocument, PolicyKindResource, resourceErrors, envelopeField{key: "Queues", target: &policy.Queues, required: true})
+}
+
+/**
+ * Writes the policy back as a {"Queues": [...], "PolicyDocument": ...} object.
+ * Absent values are omitted.
+ */
+func (policy QueuePolicy) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		Queues         []string        `json:"Queues,omitempty"`
+		PolicyDocument *PolicyDocument `json:"PolicyDocument,omitempty"`
+	}{
+		Queues:         policy.Queues,
+		PolicyDocument: policy.PolicyDocument,
+	}
+	return json.Marshal(aux)
+}
+
+/**
+ * TopicPolicy struct represents the properties of an AWS::SNS::TopicPolicy: the topic ARNs and their policy document.
+ *
+ * On top of the rules of ResourcePolicy, the resources have to be SNS ARNs or "*".
+ *
+ * for grammar see https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/aws-resource-sns-topicpolicy.html
+ */
+type TopicPolicy struct {
+	Topics         []string        `json:"Topics"`
+	PolicyDocument *PolicyDocument `json:"PolicyDocument"`
+}
+
+func (this TopicPolicy) String() string {
+	return fmt.Sprintf("TopicPolicy{Topics: %v, PolicyDocument: %s}", this.Topics, this.PolicyDocument.String())
+}
+
+/**
+ * Parses a {"Topics": [...], "PolicyDocument": ...} object.
+ * Only the first error is returned, see Validate for getting all of them.
+ */
+func (policy *TopicPolicy) UnmarshalJSON(data []byte) error {
+	return firstError(checkSource(data, policy.unmarshal(data)))
+}
+
+/**
+ * Returns every error of the topic policy instead of only the first one, the errors of the policy document
+ * coming before a missing or malformed Topics key. Returns nil if the policy is valid.
+ */
+func (policy *TopicPolicy) Validate(data []byte) []*ParseError {
+	return toParseErrors(checkSource(data, policy.unmarshal(data)))
+}
+
+func (policy *TopicPolicy) unmarshal(data []byte) []error {
+	resourceErrors := serviceResourceErrors("topic policy", "sns")
+	return unmarshalEnvelope(data, &policy.PolicyDocument, PolicyKindResource, resourceErrors, envelopeField{key: "Topics", target: &policy.Topics, required: true})
+}
+
+/**
+ * Writes the policy back as a {"Topics": [...], "PolicyDocument": ...} object.
+ * Absent values are omitted.
+ */
+func (policy TopicPolicy) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		Topics         []string        `json:"Topics,omitempty"`
+		PolicyDocument *PolicyDocument `json:"PolicyDocument,omitempty"`
+	}{
+		Topics:         policy.Topics,
+		PolicyDocument: policy.PolicyDocument,
+	}
+	return json.Marshal(aux)
+}
+
+// Returns the check of a resource policy named name whose resources have to belong to the service (or be "*")
+func serviceResourceErrors(name string, service string) func(*PolicyDocument) []error {
+	return func(pd *PolicyDocument) []error {
+		return pd.resourceErrors(name, "an "+service+` ARN or "*"`, func(arn ARN) bool {
+			return arn == WildcardARN || arn.Service == service
+		})
+	}
+}
+
+// Checks that the Resource values of the statements are allowed in a policy named name, expected describing the allowed values
+func (pd *PolicyDocument) resourceErrors(name string, expected string, allowed func(ARN) bool) []error {
+	var errs []error
+	for i, statement := range pd.StatementList() {
+		if !statement.Resource {
+			continue
+		}
+		for j, resource := range statement.resourceStrings() {
+			arn, err := ParseARN(resource)
+			// invalid resources are already reported by the statement
+			if err != nil || allowed(arn) {
+				continue
+			}
+			path := pd.statementPath(i) + elementPath("Resource", statement.ResourceValue, j)
+			errs = append(errs, newParseError(CodeInvalidResource, path, fmt.Sprintf(`resource "%s" is not allowed in a %s, it should be %s`, resource, name, expected)))
+		}
+	}
+	return errs
+}
+
+// Returns the string or "nil"
+func stringPtrString(ptr *string) string {
+	if ptr == nil {
+		return "nil"
+	}
+	return *ptr
+}
@@ -0,0 +1,129 @@
+package iamrolepolicyparsing
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func validationMessages(parseErrors []*ParseError) []string {
+	var messages []string
+	for _, parseError := range parseErrors {
+		messages = append(messages, parseError.Error())
+	}
+	return messages
+}
+
+func TestResourcePolicy_Validate(t *testing.T) {
+	data := `{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Principal": {"AWS": ["123456789012"]}, "Action": "ecr:BatchGetImage", "Resource": "*"},
+		{"Effect": "Allow", "Action": "ecr:BatchGetImage", "Resource": "*"}
+	]}`
+	var resourcePolicy ResourcePolicy
+	expected := []string{"/Statement/1/Principal: Principal or NotPrincipal is required in a resource policy"}
+
+	messages := validationMessages(resourcePolicy.Validate([]byte(data)))
+
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected: %q, got: %q", expected, messages)
+	}
+	if len(resourcePolicy.PolicyDocument.StatementList()) != 2 {
+		t.Errorf("Expected 2 statements, got: %v", resourcePolicy)
+	}
+}
+
+func TestKeyPolicy_Validate(t *testing.T) {
+	data := `{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Principal": {"AWS": ["arn:aws:iam::123456789012:root"]}, "Action": "kms:*", "Resource": "*"},
+		{"Effect": "Allow", "Principal": {"AWS": ["123456789012"]}, "Action": "kms:Decrypt", "Resource": ["*", "arn:aws:kms:us-east-1:123456789012:key/1234"]}
+	]}`
+	var keyPolicy KeyPolicy
+	expected := []string{`/Statement/1/Resource/1: resource "arn:aws:kms:us-east-1:123456789012:key/1234" is not allowed in a key policy, it should be "*"`}
+
+	messages := validationMessages(keyPolicy.Validate([]byte(data)))
+
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected: %q, got: %q", expected, messages)
+	}
+}
+
+func TestBucketPolicy_Unmarshal(t *testing.T) {
+	data := `{"Bucket": "my-bucket", "PolicyDocument": {"Version": "2012-10-17", "Statement": [
+		{"Effect": "Deny", "Principal": "*", "Action": "s3:*", "Resource": ["arn:aws:s3:::my-bucket", "arn:aws:s3:::my-bucket/*"],
+			"Condition": {"Bool": {"aws:SecureTransport": false}}}
+	]}}`
+	var bucketPolicy BucketPolicy
+
+	err := json.Unmarshal([]byte(data), &bucketPolicy)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if *bucketPolicy.Bucket != "my-bucket" || len(bucketPolicy.PolicyDocument.StatementList()) != 1 {
+		t.Errorf("Expected the policy to be parsed, got: %v", bucketPolicy)
+	}
+}
+
+func TestBucketPolicy_Validate(t *testing.T) {
+	data := `{"Bucket": 42, "Extra": true, "PolicyDocument": {"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Action": "s3:GetObject", "Resource": ["arn:aws:s3:::my-bucket/*", "arn:aws:sqs:us-east-1:123456789012:queue"]}
+	]}}`
+	var bucketPolicy BucketPolicy
+	expected := []string{
+		"/Extra: unknown key: Extra",
+		"/PolicyDocument/Statement/0/Principal: Principal or NotPrincipal is required in a resource policy",
+		`/PolicyDocument/Statement/0/Resource/1: resource "arn:aws:sqs:us-east-1:123456789012:queue" is not allowed in a bucket policy, it should be an s3 ARN or "*"`,
+		"/Bucket: Bucket should be a string",
+	}
+
+	messages := validationMessages(bucketPolicy.Validate([]byte(data)))
+
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected: %q, got: %q", expected, messages)
+	}
+}
+
+func TestBucketPolicy_MarshalJSON(t *testing.T) {
+	data := `{"Bucket":"my-bucket","PolicyDocument":{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::my-bucket/*"}]}}`
+	var bucketPolicy BucketPolicy
+	if err := json.Unmarshal([]byte(data), &bucketPolicy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+
+	marshalled, err := json.Marshal(bucketPolicy)
+
+	if err != nil || string(marshalled) != data {
+		t.Errorf("Expected: %s, got: %s (error: %v)", data, marshalled, err)
+	}
+}
+
+func TestQueuePolicy_UnmarshalWhenMissingQueues(t *testing.T) {
+	data := `{"PolicyDocument": {"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Principal": {"Service": ["sns.amazonaws.com"]}, "Action": "sqs:SendMessage", "Resource": "arn:aws:sqs:us-east-1:123456789012:queue"}
+	]}}`
+	var queuePolicy QueuePolicy
+	expectedErr := &ParseError{Code: CodeMissingKey, Path: "/Queues", Message: "Queues is required", Line: 1, Column: 1}
+
+	err := json.Unmarshal([]byte(data), &queuePolicy)
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %+v, got: %+v", expectedErr, err)
+	}
+}
+
+func TestTopicPolicy_Validate(t *testing.T) {
+	data := `{"Topics": ["arn:aws:sns:us-east-1:123456789012:topic"], "PolicyDocument": {"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Principal": {"Service": ["events.amazonaws.com"]}, "Action": "sns:Publish", "Resource": "arn:aws:s3:::bucket"}
+	]}}`
+	var topicPolicy TopicPolicy
+	expected := []string{`/PolicyDocument/Statement/0/Resource: resource "arn:aws:s3:::bucket" is not allowed in a topic policy, it should be an sns ARN or "*"`}
+
+	messages := validationMessages(topicPolicy.Validate([]byte(data)))
+
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected: %q, got: %q", expected, messages)
+	}
+	if !reflect.DeepEqual(topicPolicy.Topics, []string{"arn:aws:sns:us-east-1:123456789012:topic"}) {
+		t.Errorf("Expected the topics to be parsed, got: %v", topicPolicy.Topics)
+	}
+}
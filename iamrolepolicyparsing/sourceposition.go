@@ -0,0 +1,212 @@
+package iamrolepolicyparsing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/**
+ * Completes the errors of unmarshalling data: adds an error for every duplicate key in data (encoding/json silently
+ * keeps the last value) and sets the Line and Column of every ParseError.
+ */
+func checkSource(data []byte, errs []error) []error {
+	source := scanJSON(data)
+	return source.locate(append(source.duplicateKeyErrors(), errs...))
+}
+
+/**
+ * jsonSource struct holds what scanning a JSON document found: the byte offset of every value by its JSON pointer
+ * (the offset of its key for object members, the last one if the key is duplicated) and the paths of the duplicate keys.
+ */
+type jsonSource struct {
+	data          []byte
+	offsets       map[string]int
+	duplicateKeys []string
+}
+
+/**
+ * Scans data, stopping at the first syntax error but keeping what was found so far.
+ */
+func scanJSON(data []byte) *jsonSource {
+	scanner := &jsonScanner{data: data, source: &jsonSource{data: data, offsets: map[string]int{}}}
+	scanner.value("", -1)
+	return scanner.source
+}
+
+func (source *jsonSource) duplicateKeyErrors() []error {
+	var errs []error
+	for _, path := range source.duplicateKeys {
+		key := jsonPointerUnescaper.Replace(path[strings.LastIndexByte(path, '/')+1:])
+		errs = append(errs, newParseError(CodeDuplicateKey, path, fmt.Sprintf(`key "%s" appears more than once`, key)))
+	}
+	return errs
+}
+
+/**
+ * Sets the Line and Column of every ParseError from its path (or, for JSON syntax errors, from the offset
+ * reported by encoding/json).
+ *
+ * An error pointing at an object member is located at its key, one pointing at a missing key at the enclosing object.
+ */
+func (source *jsonSource) locate(errs []error) []error {
+	for i, err := range errs {
+		parseError, ok := err.(*ParseError)
+		if !ok || parseError.Line != 0 {
+			continue
+		}
+		offset, found := errorOffset(parseError, source.offsets)
+		if !found {
+			continue
+		}
+		located := *parseError
+		located.Line, located.Column = lineAndColumn(source.data, offset)
+		errs[i] = &located
+	}
+	return errs
+}
+
+func errorOffset(parseError *ParseError, offsets map[string]int) (int, bool) {
+	var syntaxError *json.SyntaxError
+	if errors.As(parseError.Err, &syntaxError) && parseError.Path == "" {
+		// the offset of a syntax error is the one of the byte after the error
+		return max(int(syntaxError.Offset)-1, 0), true
+	}
+	for path := parseError.Path; ; path = path[:strings.LastIndexByte(path, '/')] {
+		if offset, ok := offsets[path]; ok {
+			return offset, true
+		}
+		if path == "" {
+			return 0, false
+		}
+	}
+}
+
+// Returns the 1-based line and column (in bytes) of the offset
+func lineAndColumn(data []byte, offset int) (int, int) {
+	offset = min(offset, len(data))
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	return bytes.Count(data[:offset], []byte{'\n'}) + 1, offset - lineStart + 1
+}
+
+type jsonScanner struct {
+	data   []byte
+	pos    int
+	source *jsonSource
+}
+
+// Scans the value at the current position, recording its offset (or keyOffset if it's an object member)
+func (scanner *jsonScanner) value(path string, keyOffset int) bool {
+	scanner.skipSpace()
+	if scanner.pos >= len(scanner.data) {
+		return false
+	}
+	if keyOffset >= 0 {
+		scanner.source.offsets[path] = keyOffset
+	} else {
+		scanner.source.offsets[path] = scanner.pos
+	}
+
+	switch scanner.data[scanner.pos] {
+	case '{':
+		return scanner.object(path)
+	case '[':
+		return scanner.array(path)
+	case '"':
+		_, ok := scanner.string()
+		return ok
+	}
+	for scanner.pos < len(scanner.data) && !strings.ContainsRune(",]} \t\r\n", rune(scanner.data[scanner.pos])) {
+		scanner.pos++
+	}
+	return true
+}
+
+func (scanner *jsonScanner) object(path string) bool {
+	scanner.pos++ // '{'
+	scanner.skipSpace()
+	if scanner.consume('}') {
+		return true
+	}
+	keyCounts := map[string]int{}
+	for {
+		scanner.skipSpace()
+		keyOffset := scanner.pos
+		key, ok := scanner.string()
+		if !ok {
+			return false
+		}
+		// a key appearing three times is reported once
+		if keyCounts[key] == 1 {
+			scanner.source.duplicateKeys = append(scanner.source.duplicateKeys, jsonPointer(path, key))
+		}
+		keyCounts[key]++
+		scanner.skipSpace()
+		if !scanner.consume(':') || !scanner.value(jsonPointer(path, key), keyOffset) {
+			return false
+		}
+		scanner.skipSpace()
+		if scanner.consume('}') {
+			return true
+		}
+		if !scanner.consume(',') {
+			return false
+		}
+	}
+}
+
+func (scanner *jsonScanner) array(path string) bool {
+	scanner.pos++ // '['
+	scanner.skipSpace()
+	if scanner.consume(']') {
+		return true
+	}
+	for i := 0; ; i++ {
+		if !scanner.value(jsonPointer(path, i), -1) {
+			return false
+		}
+		scanner.skipSpace()
+		if scanner.consume(']') {
+			return true
+		}
+		if !scanner.consume(',') {
+			return false
+		}
+	}
+}
+
+// Scans a string literal and returns its decoded value
+func (scanner *jsonScanner) string() (string, bool) {
+	if scanner.pos >= len(scanner.data) || scanner.data[scanner.pos] != '"' {
+		return "", false
+	}
+	start := scanner.pos
+	for scanner.pos++; scanner.pos < len(scanner.data); scanner.pos++ {
+		switch scanner.data[scanner.pos] {
+		case '\\':
+			scanner.pos++
+		case '"':
+			scanner.pos++
+			var s string
+			err := json.Unmarshal(scanner.data[start:scanner.pos], &s)
+			return s, err == nil
+		}
+	}
+	return "", false
+}
+
+func (scanner *jsonScanner) consume(c byte) bool {
+	if scanner.pos < len(scanner.data) && scanner.data[scanner.pos] == c {
+		scanner.pos++
+		return true
+	}
+	return false
+}
+
+func (scanner *jsonScanner) skipSpace() {
+	for scanner.pos < len(scanner.data) && strings.ContainsRune(" \t\r\n", rune(scanner.data[scanner.pos])) {
+		scanner.pos++
+	}
+}
@@ -0,0 +1,113 @@
+package iamrolepolicyparsing
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+const multiLinePolicy = `{
+  "PolicyName": "name",
+  "PolicyDocument": {
+    "Version": "2012-10-17",
+    "Statement": [
+      {
+        "Effect": "Allow",
+        "Action": [
+          "s3:GetObject",
+          "s3GetObject"
+        ],
+        "Resource": "*",
+        "Unknown": true
+      },
+      {
+        "Effect": "Allow",
+        "Action": "s3:GetObject"
+      }
+    ]
+  }
+}`
+
+func TestParseError_LineAndColumn(t *testing.T) {
+	var iamRolePolicy IamRolePolicy
+	expected := []string{"13:9 /PolicyDocument/Statement/0/Unknown", "10:11 /PolicyDocument/Statement/0/Action/1", "15:7 /PolicyDocument/Statement/1/Resource"}
+
+	var positions []string
+	for _, parseError := range iamRolePolicy.Validate([]byte(multiLinePolicy)) {
+		positions = append(positions, fmt.Sprintf("%d:%d %s", parseError.Line, parseError.Column, parseError.Path))
+	}
+
+	if !reflect.DeepEqual(positions, expected) {
+		t.Errorf("Expected: %q, got: %q", expected, positions)
+	}
+}
+
+func TestParseError_LineAndColumnOfASyntaxError(t *testing.T) {
+	data := []byte("{\n  \"Effect\": \"Allow\",,\n}")
+	var stat Statement
+
+	err := stat.UnmarshalJSON(data)
+
+	var parseError *ParseError
+	if !errors.As(err, &parseError) || parseError.Line != 2 || parseError.Column != 21 {
+		t.Errorf("Expected an error at 2:21, got: %+v", err)
+	}
+}
+
+func TestScanJSON_Offsets(t *testing.T) {
+	data := []byte(`{"a": [1, {"b/c": "x\"y"}], "d": null}`)
+	expected := map[string]int{"": 0, "/a": 1, "/a/0": 7, "/a/1": 10, "/a/1/b~1c": 11, "/d": 28}
+
+	source := scanJSON(data)
+
+	if !reflect.DeepEqual(source.offsets, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, source.offsets)
+	}
+}
+
+func TestIamRolePolicy_ValidateDuplicateKeys(t *testing.T) {
+	data := `{"PolicyName": "a", "PolicyName": "b", "PolicyDocument": {"Version": "2012-10-17", "Version": "2012-10-17", "Statement": [
+		{"Effect": "Deny", "Effect": "Allow", "Effect": "Allow", "Action": "s3:*", "Resource": "*", "Principal": {"AWS": ["*"], "AWS": ["123456789012"]}}
+	]}}`
+	var iamRolePolicy IamRolePolicy
+	expected := []string{
+		`/PolicyName: key "PolicyName" appears more than once`,
+		`/PolicyDocument/Version: key "Version" appears more than once`,
+		`/PolicyDocument/Statement/0/Effect: key "Effect" appears more than once`,
+		`/PolicyDocument/Statement/0/Principal/AWS: key "AWS" appears more than once`,
+		`/PolicyDocument/Statement/0/Principal: Principal is not allowed in an identity policy`,
+	}
+
+	var messages []string
+	for _, parseError := range iamRolePolicy.Validate([]byte(data)) {
+		messages = append(messages, parseError.Error())
+	}
+
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected: %q, got: %q", expected, messages)
+	}
+}
+
+func TestStatement_UnmarshalDuplicateKey(t *testing.T) {
+	data := []byte(`{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*", "Resource": "*"}`)
+	var stat Statement
+	expectedErr := &ParseError{Code: CodeDuplicateKey, Path: "/Resource", Message: `key "Resource" appears more than once`, Line: 1, Column: 84}
+
+	err := stat.UnmarshalJSON(data)
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %+v, got: %+v", expectedErr, err)
+	}
+}
+
+func TestPolicyDocument_UnmarshalJSONDuplicateKey(t *testing.T) {
+	data := []byte(`{"Statement": [], "Statement": []}`)
+	var pd PolicyDocument
+
+	err := pd.UnmarshalJSON(data)
+
+	if !errors.Is(err, &ParseError{Code: CodeDuplicateKey, Path: "/Statement"}) {
+		t.Errorf("Expected a duplicate key error at /Statement, got: %v", err)
+	}
+}
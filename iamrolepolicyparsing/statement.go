@@ -2,7 +2,6 @@ package iamrolepolicyparsing
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"reflect"
 )
@@ -16,18 +15,29 @@ import (
  * Same for "Resource" and "NotResource", Resource and ResourceValue
  * and for "Principal" and "NotPrincipal", Principal and PrincipalValue
  *
+ * Principals is the typed form of PrincipalValue (see principal.go), nil if neither key was present.
+ * Actions holds the parsed form of every value in ActionValue, in order (see action.go).
+ * Resources holds the parsed ARN of every value in ResourceValue, in order (see arn.go).
+ * Conditions is the typed form of ConditionMap (see condition.go), nil if there was no "Condition" key.
+ * The typed forms are filled in by UnmarshalJSON. For a statement built in code, the Matches methods parse
+ * the raw values in their place.
+ *
  * for grammar see https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_grammar.html
  */
 type Statement struct {
-	Sid            *string `json:"Sid"`
-	PrincipalValue interface{}
-	Principal      bool
-	ActionValue    interface{}
-	Action         bool
-	ResourceValue  interface{}
-	Resource       bool
+	Sid            *string     `json:"Sid"`
+	PrincipalValue interface{} `json:"-"`
+	Principal      bool        `json:"-"`
+	Principals     *Principal  `json:"-"`
+	ActionValue    interface{} `json:"-"`
+	Action         bool        `json:"-"`
+	Actions        []Action    `json:"-"`
+	ResourceValue  interface{} `json:"-"`
+	Resource       bool        `json:"-"`
+	Resources      []ARN       `json:"-"`
 	Effect         *string     `json:"Effect"`
 	ConditionMap   interface{} `json:"Condition"`
+	Conditions     Condition   `json:"-"`
 }
 
 func (this *Statement) String() string {
@@ -66,131 +76,251 @@ func (this *Statement) Equals(other interface{}) bool {
 
 }
 
-func parseSid(statMap map[string]interface{}, stat *Statement) error {
+func parseSid(statMap map[string]interface{}, stat *Statement) []error {
 	if statMap["Sid"] != nil {
 		if sid, ok := statMap["Sid"].(string); !ok {
-			return errors.New("sid is a non-string")
+			return []error{newParseError(CodeInvalidType, "/Sid", "sid is a non-string")}
 		} else {
 			stat.Sid = &sid
+			if !isSid(sid) {
+				return []error{newParseError(CodeInvalidSid, "/Sid", fmt.Sprintf(`sid "%s" should contain only the characters A-Z, a-z and 0-9`, sid))}
+			}
 		}
 	}
 	return nil
 }
 
-func parseEffect(statMap map[string]interface{}, stat *Statement) error {
+func parseEffect(statMap map[string]interface{}, stat *Statement) []error {
 	if effect, ok := statMap["Effect"].(string); !ok {
-		return errors.New("effect is absent or a non-string")
+		code := CodeInvalidType
+		if statMap["Effect"] == nil {
+			code = CodeMissingKey
+		}
+		return []error{newParseError(code, "/Effect", "effect is absent or a non-string")}
 	} else {
 		if effect != "Allow" && effect != "Deny" {
-			return errors.New(`effect should be either "Allow" or "Deny"`)
+			return []error{newParseError(CodeInvalidEffect, "/Effect", `effect should be either "Allow" or "Deny"`)}
 		}
 		stat.Effect = &effect
 	}
 	return nil
 }
 
-func parsePrincipal(statMap map[string]interface{}, stat *Statement) error {
+func parsePrincipal(statMap map[string]interface{}, stat *Statement) []error {
+	key := "Principal"
 	if statMap["Principal"] != nil {
 		stat.PrincipalValue = statMap["Principal"]
 		stat.Principal = true
 	}
 	if statMap["NotPrincipal"] != nil {
 		if stat.PrincipalValue != nil {
-			return errors.New("principal and not-principal value shouldn't exist within a single statement block")
+			return []error{newParseError(CodeConflictingKeys, "/NotPrincipal", "principal and not-principal value shouldn't exist within a single statement block")}
 		}
 		stat.PrincipalValue = statMap["NotPrincipal"]
 		stat.Principal = false
+		key = "NotPrincipal"
 	}
+	path := jsonPointer("", key)
 	if principalString, ok := stat.PrincipalValue.(string); ok {
 		if principalString != "*" {
-			return errors.New("principal value should be '*' or a map")
+			return []error{newParseError(CodeInvalidPrincipal, path, "principal value should be '*' or a map")}
 		}
+		stat.Principals = &Principal{Wildcard: true, NotPrincipal: !stat.Principal}
 	} else if principalMap, ok := stat.PrincipalValue.(map[string]interface{}); ok {
-		for key, value := range principalMap {
+		var errs []error
+		principal := &Principal{NotPrincipal: !stat.Principal}
+		for _, key := range sortedKeys(principalMap) {
+			value := principalMap[key]
 			if key != "AWS" && key != "Federated" && key != "Service" && key != "CanonicalUser" {
-				return errors.New(`key in principal map should be one of the following: "AWS", "Federated", "Service", "CanonicalUser"`)
+				errs = append(errs, newParseError(CodeInvalidPrincipal, jsonPointer(path, key), `key in principal map should be one of the following: "AWS", "Federated", "Service", "CanonicalUser"`))
+				continue
 			}
-			if array, ok := value.([]interface{}); ok {
-				for _, principalIdString := range array {
-					if _, ok := principalIdString.(string); !ok {
-						return errors.New("value in principal map should be a []string")
-					}
+			// like Action and Resource, a single principal ID can be given as a string
+			if !isStringOrList(value) {
+				errs = append(errs, newParseError(CodeInvalidType, jsonPointer(path, key), "value in principal map should either be a string or a []string"))
+				continue
+			}
+			var ids []string
+			for i, principalIdString := range stringOrListElements(value) {
+				idPath := path + elementPath(key, value, i)
+				id, ok := principalIdString.(string)
+				if !ok {
+					errs = append(errs, newParseError(CodeInvalidType, idPath, "value in principal map should be a []string"))
+					continue
+				}
+				if err := validatePrincipalId(key, id); err != nil {
+					errs = append(errs, wrapParseError(CodeInvalidPrincipal, idPath, err))
+					continue
 				}
-			} else {
-				return errors.New("value in principal map should be an array")
+				ids = append(ids, id)
+			}
+			switch key {
+			case "AWS":
+				principal.AWS = ids
+				principal.Wildcard = containsString(ids, "*")
+			case "Federated":
+				principal.Federated = ids
+			case "Service":
+				principal.Service = ids
+			case "CanonicalUser":
+				principal.CanonicalUser = ids
 			}
 		}
+		stat.Principals = principal
+		return errs
 	} else if stat.PrincipalValue != nil {
-		return errors.New("principal value should be '*' or a map")
+		return []error{newParseError(CodeInvalidType, path, "principal value should be '*' or a map")}
 	}
 	return nil
 }
 
-func parseAction(statMap map[string]interface{}, stat *Statement) error {
+func parseAction(statMap map[string]interface{}, stat *Statement) []error {
+	key := "Action"
 	if statMap["Action"] != nil {
 		stat.ActionValue = statMap["Action"]
 		stat.Action = true
 	}
 	if statMap["NotAction"] != nil {
 		if stat.ActionValue != nil {
-			return errors.New("action and not-action shouldn't exist within a single statement block")
+			return []error{newParseError(CodeConflictingKeys, "/NotAction", "action and not-action shouldn't exist within a single statement block")}
 		}
 		stat.ActionValue = statMap["NotAction"]
 		stat.Action = false
+		key = "NotAction"
 	}
 	if stat.ActionValue == nil {
-		return errors.New("action or not-action has to exist in a statement block")
+		return []error{newParseError(CodeMissingKey, "/Action", "action or not-action has to exist in a statement block")}
 	}
-	switch stat.ActionValue.(type) {
-	case string:
-	case []interface{}:
-		for _, action := range stat.ActionValue.([]interface{}) {
-			if _, ok := action.(string); !ok {
-				return errors.New("action value should be a []string")
-			}
+	if !isStringOrList(stat.ActionValue) {
+		return []error{newParseError(CodeInvalidType, jsonPointer("", key), "action value should either be a string or a []string")}
+	}
+
+	var errs []error
+	stat.Actions = nil
+	for i, element := range stringOrListElements(stat.ActionValue) {
+		path := elementPath(key, stat.ActionValue, i)
+		actionString, ok := element.(string)
+		if !ok {
+			errs = append(errs, newParseError(CodeInvalidType, path, "action value should be a []string"))
+			continue
 		}
-	default:
-		return errors.New("action value should either be a string or a []string")
+		action, err := ParseAction(actionString)
+		if err != nil {
+			errs = append(errs, wrapParseError(CodeInvalidAction, path, err))
+			continue
+		}
+		stat.Actions = append(stat.Actions, action)
 	}
-	return nil
+	return errs
 }
 
-func parseResource(statMap map[string]interface{}, stat *Statement) error {
+func parseResource(statMap map[string]interface{}, stat *Statement) []error {
+	key := "Resource"
 	if statMap["Resource"] != nil {
 		stat.ResourceValue = statMap["Resource"]
 		stat.Resource = true
 	}
 	if statMap["NotResource"] != nil {
 		if stat.ResourceValue != nil {
-			return errors.New("resource and not-resource shouldn't exist within a single statement block")
+			return []error{newParseError(CodeConflictingKeys, "/NotResource", "resource and not-resource shouldn't exist within a single statement block")}
 		}
 		stat.ResourceValue = statMap["NotResource"]
 		stat.Resource = false
+		key = "NotResource"
 	}
 	if stat.ResourceValue == nil {
-		return errors.New("resource or not-resource has to exist in a statement block")
+		return []error{newParseError(CodeMissingKey, "/Resource", "resource or not-resource has to exist in a statement block")}
+	}
+	if !isStringOrList(stat.ResourceValue) {
+		return []error{newParseError(CodeInvalidType, jsonPointer("", key), "resource value should either be a string or a []string")}
+	}
+
+	var errs []error
+	stat.Resources = nil
+	for i, element := range stringOrListElements(stat.ResourceValue) {
+		path := elementPath(key, stat.ResourceValue, i)
+		resource, ok := element.(string)
+		if !ok {
+			errs = append(errs, newParseError(CodeInvalidType, path, "resource value should be a []string"))
+			continue
+		}
+		if _, err := ParsePolicyVariables(resource); err != nil {
+			errs = append(errs, wrapParseError(CodeInvalidPolicyVariable, path, err))
+			continue
+		}
+		arn, err := ParseARN(resource)
+		if err != nil {
+			errs = append(errs, wrapParseError(CodeInvalidResource, path, err))
+			continue
+		}
+		stat.Resources = append(stat.Resources, arn)
+	}
+	return errs
+}
+
+func parseCondition(statMap map[string]interface{}, stat *Statement) []error {
+	stat.ConditionMap = statMap["Condition"]
+	stat.Conditions = nil
+	if stat.ConditionMap == nil {
+		return nil
+	}
+
+	condition, errs := newCondition(stat.ConditionMap)
+	stat.Conditions = condition
+	return prefixErrorPaths("/Condition", errs)
+}
+
+func isSid(sid string) bool {
+	for _, c := range sid {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Whether the value is a string or an array (of anything, the elements are checked one by one)
+func isStringOrList(value interface{}) bool {
+	switch value.(type) {
+	case string, []interface{}:
+		return true
 	}
-	switch stat.ResourceValue.(type) {
+	return false
+}
+
+// The elements of a string or an array value, a string being a single element
+func stringOrListElements(value interface{}) []interface{} {
+	switch value := value.(type) {
 	case string:
+		return []interface{}{value}
 	case []interface{}:
-		for _, resource := range stat.ResourceValue.([]interface{}) {
-			if _, ok := resource.(string); !ok {
-				return errors.New("resource value should be a []string")
-			}
-		}
-	default:
-		return errors.New("resource value should either be a string or a []string")
+		return value
 	}
 	return nil
 }
 
-func parseCondition(statMap map[string]interface{}, stat *Statement) {
-	stat.ConditionMap = statMap["Condition"]
+// The path of the i-th value of a key holding a string or an array of strings
+func elementPath(key string, value interface{}, i int) string {
+	if _, ok := value.([]interface{}); ok {
+		return jsonPointer("", key, i)
+	}
+	return jsonPointer("", key)
 }
 
 // UnmarshalJSON function
 
+/**
+ * Parses a statement in the policy grammar.
+ *
+ * Errors are *ParseError values (see parseerror.go) with paths relative to the statement, e.g. "/Resource/1".
+ * Only the first error is returned, see IamRolePolicy.Validate for getting all of them.
+ */
 func (stat *Statement) UnmarshalJSON(data []byte) error {
+	return firstError(checkSource(data, stat.unmarshal(data)))
+}
+
+// Parses the statement, returning every error found
+func (stat *Statement) unmarshal(data []byte) []error {
 	// reference: https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_grammar.html
 
 	// decode.go/line 117
@@ -203,11 +333,12 @@ func (stat *Statement) UnmarshalJSON(data []byte) error {
 	var statMap map[string]interface{}
 	err := json.Unmarshal(data, &statMap)
 	if err != nil {
-		return err
+		return []error{jsonParseError("", err)}
 	}
 
+	var errs []error
 	// Ensure no unwanted properties exist in data
-	for key, _ := range statMap {
+	for _, key := range sortedKeys(statMap) {
 		if key != "Sid" &&
 			key != "Principal" &&
 			key != "Action" &&
@@ -217,40 +348,316 @@ func (stat *Statement) UnmarshalJSON(data []byte) error {
 			key != "Condition" &&
 			key != "NotResource" &&
 			key != "NotPrincipal" {
-			return errors.New(fmt.Sprintf("unknown key: %s", key))
+			errs = append(errs, newParseError(CodeUnknownKey, jsonPointer("", key), fmt.Sprintf("unknown key: %s", key)))
 		}
 	}
 
-	if err := parseSid(statMap, stat); err != nil {
-		return err
-	}
-	if err := parseEffect(statMap, stat); err != nil {
-		return err
+	errs = append(errs, parseSid(statMap, stat)...)
+	errs = append(errs, parseEffect(statMap, stat)...)
+	errs = append(errs, parsePrincipal(statMap, stat)...)
+	errs = append(errs, parseAction(statMap, stat)...)
+	errs = append(errs, parseResource(statMap, stat)...)
+	errs = append(errs, parseCondition(statMap, stat)...)
+
+	return errs
+}
+
+// MarshalJSON function
+
+/**
+ * Writes the statement back in the policy grammar.
+ *
+ * The Principal, Action and Resource booleans decide whether the values are written under
+ * "Principal"/"NotPrincipal", "Action"/"NotAction" and "Resource"/"NotResource" respectively.
+ * Absent values are omitted.
+ */
+func (stat Statement) MarshalJSON() ([]byte, error) {
+	aux := struct {
+		Sid          *string     `json:"Sid,omitempty"`
+		Effect       *string     `json:"Effect,omitempty"`
+		Principal    interface{} `json:"Principal,omitempty"`
+		NotPrincipal interface{} `json:"NotPrincipal,omitempty"`
+		Action       interface{} `json:"Action,omitempty"`
+		NotAction    interface{} `json:"NotAction,omitempty"`
+		Resource     interface{} `json:"Resource,omitempty"`
+		NotResource  interface{} `json:"NotResource,omitempty"`
+		Condition    interface{} `json:"Condition,omitempty"`
+	}{
+		Sid:       stat.Sid,
+		Effect:    stat.Effect,
+		Condition: stat.ConditionMap,
 	}
-	if err := parsePrincipal(statMap, stat); err != nil {
-		return err
+
+	if stat.Principal {
+		aux.Principal = stat.PrincipalValue
+	} else {
+		aux.NotPrincipal = stat.PrincipalValue
 	}
-	if err := parseAction(statMap, stat); err != nil {
-		return err
+	if stat.Action {
+		aux.Action = stat.ActionValue
+	} else {
+		aux.NotAction = stat.ActionValue
 	}
-	if err := parseResource(statMap, stat); err != nil {
-		return err
+	if stat.Resource {
+		aux.Resource = stat.ResourceValue
+	} else {
+		aux.NotResource = stat.ResourceValue
 	}
-	parseCondition(statMap, stat)
 
+	return json.Marshal(aux)
+}
+
+// Returns ActionValue as a list of strings, a single string value becomes a one element list
+func (stat Statement) actionStrings() []string {
+	return toStringList(stat.ActionValue)
+}
+
+// Returns ResourceValue as a list of strings, a single string value becomes a one element list
+func (stat Statement) resourceStrings() []string {
+	return toStringList(stat.ResourceValue)
+}
+
+func toStringList(stringOrList interface{}) []string {
+	switch value := stringOrList.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		var values []string
+		for _, element := range value {
+			if elementString, ok := element.(string); ok {
+				values = append(values, elementString)
+			}
+		}
+		return values
+	case []string:
+		return value
+	}
 	return nil
 }
 
-func (stat Statement) isResourceAWildcard() bool {
+/**
+ * Returns the indexes of the Resource values that are a wildcard ('*').
+ * A single string value has index 0. Returns nil if NotResource was present instead of Resource.
+ */
+func (stat Statement) wildcardResourceIndexes() []int {
 	// if NotResource was present instead of Resource
 	if !stat.Resource {
-		return false
+		return nil
 	}
 
-	if stat.Resource {
-		if resourceString, ok := stat.ResourceValue.(string); ok {
-			return resourceString == "*"
+	var indexes []int
+	for i, resource := range stat.resourceStrings() {
+		if resource == "*" {
+			indexes = append(indexes, i)
 		}
 	}
-	return false
+	return indexes
+}
+
+/**
+ * Returns the indexes of the Action values that are a wildcard ('*').
+ * A single string value has index 0. Returns nil if NotAction was present instead of Action.
+ */
+func (stat Statement) wildcardActionIndexes() []int {
+	return stat.actionIndexes(func(action Action) bool { return action == WildcardAction })
+}
+
+/**
+ * Returns the indexes of the Action values that cover a whole service (e.g. "iam:*").
+ * A single string value has index 0. Returns nil if NotAction was present instead of Action.
+ */
+func (stat Statement) serviceWideActionIndexes() []int {
+	return stat.actionIndexes(Action.IsServiceWide)
+}
+
+// Returns the indexes of the Action values whose parsed form satisfies the predicate, skipping values that don't parse.
+// The indexes are positions in ActionValue, not in Actions, which lacks the invalid values.
+func (stat Statement) actionIndexes(predicate func(Action) bool) []int {
+	if !stat.Action {
+		return nil
+	}
+
+	var indexes []int
+	for i, actionString := range stat.actionStrings() {
+		action, err := ParseAction(actionString)
+		if err == nil && predicate(action) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+func (stat Statement) isResourceAWildcard() bool {
+	return len(stat.wildcardResourceIndexes()) > 0
+}
+
+/**
+ * Returns the breadth of the broadest Resource value of the statement (see resourcebreadth.go).
+ *
+ * A statement with NotResource matches everything except the listed resources, so it is classified as BreadthGlobal.
+ */
+func (stat Statement) ResourceBreadth() ResourceBreadth {
+	if !stat.Resource {
+		return BreadthGlobal
+	}
+
+	breadth := BreadthExact
+	for _, resource := range stat.resourceStrings() {
+		if resourceBreadth := ClassifyResource(resource); resourceBreadth > breadth {
+			breadth = resourceBreadth
+		}
+	}
+	return breadth
+}
+
+/**
+ * Returns the accounts named in the Resources of the statement, in order of appearance and without duplicates.
+ * Empty and wildcard segments are skipped, so e.g. S3 bucket ARNs contribute nothing.
+ */
+func (stat Statement) ResourceAccounts() []string {
+	return stat.distinctResourceSegments(func(arn ARN) string { return arn.Account })
+}
+
+/**
+ * Returns the regions named in the Resources of the statement, in order of appearance and without duplicates.
+ * Empty and wildcard segments are skipped.
+ */
+func (stat Statement) ResourceRegions() []string {
+	return stat.distinctResourceSegments(func(arn ARN) string { return arn.Region })
+}
+
+/**
+ * Returns the services named in the Resources of the statement, in order of appearance and without duplicates.
+ * Wildcard segments are skipped.
+ */
+func (stat Statement) ResourceServices() []string {
+	return stat.distinctResourceSegments(func(arn ARN) string { return arn.Service })
+}
+
+func (stat Statement) distinctResourceSegments(segment func(ARN) string) []string {
+	var values []string
+	for _, arn := range stat.Resources {
+		value := segment(arn)
+		if value != "" && !containsWildcard(value) && !containsString(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Returns the statement with Principals, Actions, Resources and Conditions parsed from the raw values where they are
+// missing, as for a statement built in code instead of by UnmarshalJSON. Values that don't parse are left out, as in UnmarshalJSON.
+func (stat Statement) withParsedValues() Statement {
+	statMap := map[string]interface{}{}
+	setKey := func(key string, notKey string, isKey bool, value interface{}) {
+		if !isKey {
+			key = notKey
+		}
+		statMap[key] = value
+	}
+	setKey("Principal", "NotPrincipal", stat.Principal, stat.PrincipalValue)
+	setKey("Action", "NotAction", stat.Action, stat.ActionValue)
+	setKey("Resource", "NotResource", stat.Resource, stat.ResourceValue)
+	statMap["Condition"] = stat.ConditionMap
+
+	if stat.Principals == nil && stat.PrincipalValue != nil {
+		parsePrincipal(statMap, &stat)
+	}
+	if stat.Actions == nil && stat.ActionValue != nil {
+		parseAction(statMap, &stat)
+	}
+	if stat.Resources == nil && stat.ResourceValue != nil {
+		parseResource(statMap, &stat)
+	}
+	if stat.Conditions == nil && stat.ConditionMap != nil {
+		parseCondition(statMap, &stat)
+	}
+	return stat
+}
+
+/**
+ * Returns whether the statement covers the given action name (e.g. "s3:GetObject").
+ *
+ * With Action, the name has to match one of the actions. With NotAction, it must match none of them.
+ */
+func (stat Statement) MatchesAction(actionName string) bool {
+	for _, action := range stat.withParsedValues().Actions {
+		if action.Matches(actionName) {
+			return stat.Action
+		}
+	}
+	return !stat.Action
+}
+
+/**
+ * Returns whether the statement covers the given resource ARN.
+ *
+ * With Resource, the ARN has to match one of the resources. With NotResource, it must match none of them.
+ * Resources with policy variables never match, see MatchesResourceInContext.
+ */
+func (stat Statement) MatchesResource(resource string) bool {
+	return stat.MatchesResourceInContext(resource, nil)
+}
+
+/**
+ * Like MatchesResource, but first substitutes the policy variables of the resources from the request context
+ * (see ARN.MatchesInContext).
+ */
+func (stat Statement) MatchesResourceInContext(resource string, context RequestContext) bool {
+	for _, arn := range stat.withParsedValues().Resources {
+		if arn.MatchesInContext(resource, context) {
+			return stat.Resource
+		}
+	}
+	return !stat.Resource
+}
+
+/**
+ * Returns whether the statement names the given principal. A statement without Principal or NotPrincipal
+ * (as in identity-based policies), or an empty principal, matches any principal.
+ */
+func (stat Statement) MatchesPrincipal(principal string) bool {
+	principals := stat.withParsedValues().Principals
+	if principals == nil || principal == "" {
+		return true
+	}
+	return principals.Matches(principal)
+}
+
+/**
+ * Evaluates the Condition block of the statement against the request context (see conditionevaluation.go).
+ * Returns nil if the statement has no Condition block.
+ */
+func (stat Statement) EvaluateConditions(context RequestContext) []ConditionResult {
+	return stat.withParsedValues().Conditions.Evaluate(context)
+}
+
+/**
+ * Returns whether every condition of the statement is matched by the request context.
+ * A statement without a Condition block always matches.
+ */
+func (stat Statement) MatchesConditions(context RequestContext) bool {
+	return stat.withParsedValues().Conditions.IsSatisfied(context)
+}
+
+/**
+ * Returns the policy variables used in the Resource/NotResource and Condition values of the statement,
+ * in order of appearance and including the ${*}, ${?} and ${$} escapes.
+ */
+func (stat Statement) PolicyVariables() []PolicyVariable {
+	var variables []PolicyVariable
+	for _, resource := range stat.resourceStrings() {
+		resourceVariables, _ := ParsePolicyVariables(resource)
+		variables = append(variables, resourceVariables...)
+	}
+	for _, operator := range stat.Conditions.Operators() {
+		keys := stat.Conditions[operator.Name]
+		for _, key := range sortedKeys(keys) {
+			for _, value := range keys[key] {
+				valueVariables, _ := ParsePolicyVariables(value)
+				variables = append(variables, valueVariables...)
+			}
+		}
+	}
+	return variables
 }
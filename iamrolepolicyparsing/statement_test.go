@@ -199,7 +199,7 @@ func TestStatement_UnmarshalInvalidJSON(t *testing.T) {
 func TestStatement_UnmarshalUnwantedProperty(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket","Unwanted":"property"}`)
 	var stat Statement
-	expectedErr := errors.New("unknown key: Unwanted")
+	expectedErr := &ParseError{Code: CodeUnknownKey, Path: "/Unwanted", Message: "unknown key: Unwanted", Line: 1, Column: 160}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -211,7 +211,7 @@ func TestStatement_UnmarshalUnwantedProperty(t *testing.T) {
 func TestStatement_UnmarshalMissingEffect(t *testing.T) {
 	data := []byte(`{"Sid":"123","Principal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New("effect is absent or a non-string")
+	expectedErr := &ParseError{Code: CodeMissingKey, Path: "/Effect", Message: "effect is absent or a non-string", Line: 1, Column: 1}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -223,7 +223,7 @@ func TestStatement_UnmarshalMissingEffect(t *testing.T) {
 func TestStatement_UnmarshalNonStringEffect(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":123,"Principal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New("effect is absent or a non-string")
+	expectedErr := &ParseError{Code: CodeInvalidType, Path: "/Effect", Message: "effect is absent or a non-string", Line: 1, Column: 14}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -235,7 +235,7 @@ func TestStatement_UnmarshalNonStringEffect(t *testing.T) {
 func TestStatement_UnmarshalInvalidEffect(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":"Invalid","Principal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New(`effect should be either "Allow" or "Deny"`)
+	expectedErr := &ParseError{Code: CodeInvalidEffect, Path: "/Effect", Message: `effect should be either "Allow" or "Deny"`, Line: 1, Column: 14}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -247,7 +247,19 @@ func TestStatement_UnmarshalInvalidEffect(t *testing.T) {
 func TestStatement_UnmarshalInvalidSidType(t *testing.T) {
 	data := []byte(`{"Sid":123,"Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New("sid is a non-string")
+	expectedErr := &ParseError{Code: CodeInvalidType, Path: "/Sid", Message: "sid is a non-string", Line: 1, Column: 2}
+
+	err := stat.UnmarshalJSON(data)
+
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("Expected error: %v, got: %v", expectedErr, err)
+	}
+}
+
+func TestStatement_UnmarshalInvalidSid(t *testing.T) {
+	data := []byte(`{"Sid":"Read-Only Access","Effect":"Allow","Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}`)
+	var stat Statement
+	expectedErr := &ParseError{Code: CodeInvalidSid, Path: "/Sid", Message: `sid "Read-Only Access" should contain only the characters A-Z, a-z and 0-9`, Line: 1, Column: 2}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -259,7 +271,7 @@ func TestStatement_UnmarshalInvalidSidType(t *testing.T) {
 func TestStatement_UnmarshalInvalidActionType(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"Action":123,"Resource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New("action value should either be a string or a []string")
+	expectedErr := &ParseError{Code: CodeInvalidType, Path: "/Action", Message: "action value should either be a string or a []string", Line: 1, Column: 94}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -271,7 +283,7 @@ func TestStatement_UnmarshalInvalidActionType(t *testing.T) {
 func TestStatement_UnmarshalPrincipalAndNotPrincipalExist(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"NotPrincipal":{"AWS":["arn:aws:iam::123456789012:user/JaneDoe"]},"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New("principal and not-principal value shouldn't exist within a single statement block")
+	expectedErr := &ParseError{Code: CodeConflictingKeys, Path: "/NotPrincipal", Message: "principal and not-principal value shouldn't exist within a single statement block", Line: 1, Column: 94}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -283,7 +295,7 @@ func TestStatement_UnmarshalPrincipalAndNotPrincipalExist(t *testing.T) {
 func TestStatement_UnmarshalActionAndNotActionExist(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"Action":["s3:ListBucket"],"NotAction":["s3:GetObject"],"Resource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New("action and not-action shouldn't exist within a single statement block")
+	expectedErr := &ParseError{Code: CodeConflictingKeys, Path: "/NotAction", Message: "action and not-action shouldn't exist within a single statement block", Line: 1, Column: 121}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -295,7 +307,7 @@ func TestStatement_UnmarshalActionAndNotActionExist(t *testing.T) {
 func TestStatement_UnmarshalResourceAndNotResourceExist(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket","NotResource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New("resource and not-resource shouldn't exist within a single statement block")
+	expectedErr := &ParseError{Code: CodeConflictingKeys, Path: "/NotResource", Message: "resource and not-resource shouldn't exist within a single statement block", Line: 1, Column: 160}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -307,7 +319,7 @@ func TestStatement_UnmarshalResourceAndNotResourceExist(t *testing.T) {
 func TestStatement_UnmarshalInvalidResourceType(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"Action":"s3:ListBucket","Resource":123}`)
 	var stat Statement
-	expectedErr := errors.New("resource value should either be a string or a []string")
+	expectedErr := &ParseError{Code: CodeInvalidType, Path: "/Resource", Message: "resource value should either be a string or a []string", Line: 1, Column: 119}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -319,7 +331,7 @@ func TestStatement_UnmarshalInvalidResourceType(t *testing.T) {
 func TestStatement_UnmarshalPrincipalValueIsNonAsteriskString(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":"lol","Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New("principal value should be '*' or a map")
+	expectedErr := &ParseError{Code: CodeInvalidPrincipal, Path: "/Principal", Message: "principal value should be '*' or a map", Line: 1, Column: 31}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -331,7 +343,7 @@ func TestStatement_UnmarshalPrincipalValueIsNonAsteriskString(t *testing.T) {
 func TestStatement_UnmarshalPrincipalValueIsOfInvalidType(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":123,"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New("principal value should be '*' or a map")
+	expectedErr := &ParseError{Code: CodeInvalidType, Path: "/Principal", Message: "principal value should be '*' or a map", Line: 1, Column: 31}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -343,7 +355,7 @@ func TestStatement_UnmarshalPrincipalValueIsOfInvalidType(t *testing.T) {
 func TestStatement_UnmarshalPrincipalMapValueIsNotAStringSlice(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":{"AWS":[1,"arn:aws:iam::123456789012:user/JohnDoe"]},"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New("value in principal map should be a []string")
+	expectedErr := &ParseError{Code: CodeInvalidType, Path: "/Principal/AWS/0", Message: "value in principal map should be a []string", Line: 1, Column: 51}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -352,10 +364,10 @@ func TestStatement_UnmarshalPrincipalMapValueIsNotAStringSlice(t *testing.T) {
 	}
 }
 
-func TestStatement_UnmarshalPrincipalMapValueIsNotASlice(t *testing.T) {
-	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:user/JohnDoe"},"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}`)
+func TestStatement_UnmarshalPrincipalMapValueIsNotAStringOrASlice(t *testing.T) {
+	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":{"AWS":123456789012},"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New("value in principal map should be an array")
+	expectedErr := &ParseError{Code: CodeInvalidType, Path: "/Principal/AWS", Message: "value in principal map should either be a string or a []string", Line: 1, Column: 44}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -364,10 +376,36 @@ func TestStatement_UnmarshalPrincipalMapValueIsNotASlice(t *testing.T) {
 	}
 }
 
+func TestStatement_UnmarshalPrincipalMapValueIsAString(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole","Resource":"*"}`)
+	var stat Statement
+	expected := &Principal{Service: []string{"ec2.amazonaws.com"}}
+
+	err := stat.UnmarshalJSON(data)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if !reflect.DeepEqual(stat.Principals, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, stat.Principals)
+	}
+}
+
+func TestStatement_UnmarshalPrincipalMapStringIsValidated(t *testing.T) {
+	data := []byte(`{"Effect":"Allow","Principal":{"Service":"ec2"},"Action":"sts:AssumeRole","Resource":"*"}`)
+	var stat Statement
+
+	err := stat.UnmarshalJSON(data)
+
+	if !errors.Is(err, &ParseError{Code: CodeInvalidPrincipal, Path: "/Principal/Service"}) {
+		t.Errorf("Expected an invalid principal error at /Principal/Service, got: %v", err)
+	}
+}
+
 func TestStatement_UnmarshalPrincipalMapKeyIsNotValid(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":{"Invalid":["arn:aws:iam::123456789012:user/JohnDoe"]},"Action":"s3:ListBucket","Resource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New(`key in principal map should be one of the following: "AWS", "Federated", "Service", "CanonicalUser"`)
+	expectedErr := &ParseError{Code: CodeInvalidPrincipal, Path: "/Principal/Invalid", Message: `key in principal map should be one of the following: "AWS", "Federated", "Service", "CanonicalUser"`, Line: 1, Column: 44}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -379,7 +417,7 @@ func TestStatement_UnmarshalPrincipalMapKeyIsNotValid(t *testing.T) {
 func TestStatement_UnmarshalActionOrAndNotActionNotExisting(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"Resource":"arn:aws:s3:::example-bucket"}`)
 	var stat Statement
-	expectedErr := errors.New("action or not-action has to exist in a statement block")
+	expectedErr := &ParseError{Code: CodeMissingKey, Path: "/Action", Message: "action or not-action has to exist in a statement block", Line: 1, Column: 1}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -391,7 +429,7 @@ func TestStatement_UnmarshalActionOrAndNotActionNotExisting(t *testing.T) {
 func TestStatement_UnmarshalMissingResourceOrNotResource(t *testing.T) {
 	data := []byte(`{"Sid":"123","Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"Action":"s3:ListBucket"}`)
 	var stat Statement
-	expectedErr := errors.New("resource or not-resource has to exist in a statement block")
+	expectedErr := &ParseError{Code: CodeMissingKey, Path: "/Resource", Message: "resource or not-resource has to exist in a statement block", Line: 1, Column: 1}
 
 	err := stat.UnmarshalJSON(data)
 
@@ -459,6 +497,38 @@ func TestStatement_IsResourceAWildcardIfItsAnArray(t *testing.T) {
 		ActionValue:    []interface{}{"s3:ListBucket"},
 		ResourceValue:  []interface{}{"*"},
 	}
+	if !stat.isResourceAWildcard() {
+		t.Errorf("Expected: true, got: false")
+	}
+}
+
+func TestStatement_IsResourceAWildcardIfOneOfArrayElements(t *testing.T) {
+	stat := Statement{
+		Sid:            stringOf("123"),
+		Effect:         stringOf("Allow"),
+		Principal:      true,
+		Action:         true,
+		Resource:       true,
+		PrincipalValue: map[string]interface{}{"AWS": []string{"arn:aws:iam::123456789012:user/JohnDoe"}},
+		ActionValue:    []interface{}{"s3:ListBucket"},
+		ResourceValue:  []interface{}{"arn:aws:s3:::bucket/*", "*"},
+	}
+	if !stat.isResourceAWildcard() {
+		t.Errorf("Expected: true, got: false")
+	}
+	if !reflect.DeepEqual(stat.wildcardResourceIndexes(), []int{1}) {
+		t.Errorf("Expected: [1], got: %v", stat.wildcardResourceIndexes())
+	}
+}
+
+func TestStatement_IsResourceAWildcardIfArrayWithoutWildcard(t *testing.T) {
+	stat := Statement{
+		Effect:        stringOf("Allow"),
+		Action:        true,
+		Resource:      true,
+		ActionValue:   []interface{}{"s3:ListBucket"},
+		ResourceValue: []interface{}{"arn:aws:s3:::bucket/*", "arn:aws:s3:::bucket"},
+	}
 	if stat.isResourceAWildcard() {
 		t.Errorf("Expected: false, got: true")
 	}
@@ -510,3 +580,66 @@ func TestStatement_IsResourceAWildcardIfNotResourceExists(t *testing.T) {
 		t.Errorf("Expected: false, got: true")
 	}
 }
+
+func TestStatement_MarshalJSON(t *testing.T) {
+	stat := Statement{
+		Sid:            stringOf("123"),
+		Effect:         stringOf("Allow"),
+		Principal:      true,
+		Action:         true,
+		Resource:       true,
+		PrincipalValue: map[string]interface{}{"AWS": []interface{}{"arn:aws:iam::123456789012:user/JohnDoe"}},
+		ActionValue:    []interface{}{"s3:ListBucket"},
+		ResourceValue:  "arn:aws:s3:::example-bucket",
+		ConditionMap:   map[string]interface{}{"Bool": map[string]interface{}{"aws:SecureTransport": "true"}},
+	}
+	expected := `{"Sid":"123","Effect":"Allow","Principal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"Action":["s3:ListBucket"],"Resource":"arn:aws:s3:::example-bucket","Condition":{"Bool":{"aws:SecureTransport":"true"}}}`
+
+	data, err := json.Marshal(stat)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if string(data) != expected {
+		t.Errorf("Expected: \n%s\n\t, got: \n%s", expected, string(data))
+	}
+}
+
+func TestStatement_MarshalJSONNegatedKeys(t *testing.T) {
+	stat := Statement{
+		Effect:         stringOf("Deny"),
+		Principal:      false,
+		Action:         false,
+		Resource:       false,
+		PrincipalValue: "*",
+		ActionValue:    "s3:ListBucket",
+		ResourceValue:  []interface{}{"arn:aws:s3:::example-bucket"},
+	}
+	expected := `{"Effect":"Deny","NotPrincipal":"*","NotAction":"s3:ListBucket","NotResource":["arn:aws:s3:::example-bucket"]}`
+
+	data, err := json.Marshal(stat)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if string(data) != expected {
+		t.Errorf("Expected: \n%s\n\t, got: \n%s", expected, string(data))
+	}
+}
+
+func TestStatement_MarshalJSONRoundTrip(t *testing.T) {
+	data := []byte(`{"Sid":"123","Effect":"Allow","NotPrincipal":{"AWS":["arn:aws:iam::123456789012:user/JohnDoe"]},"NotAction":["s3:ListBucket"],"NotResource":"arn:aws:s3:::example-bucket"}`)
+	var stat Statement
+	if err := stat.UnmarshalJSON(data); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+
+	marshalled, err := json.Marshal(stat)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if string(marshalled) != string(data) {
+		t.Errorf("Expected: \n%s\n\t, got: \n%s", string(data), string(marshalled))
+	}
+}
@@ -0,0 +1,191 @@
+package iamrolepolicyparsing
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+/**
+ * TrustPolicy struct represents the trust policy of an IAM role (the AssumeRolePolicyDocument of an AWS::IAM::Role),
+ * the policy document deciding who may assume the role.
+ *
+ * On top of the checks of PolicyDocument, every statement has to name a principal, the actions are limited to
+ * sts:AssumeRole* and sts:TagSession and Resource is not allowed (the resource is the role itself).
+ *
+ * for grammar see https://docs.aws.amazon.com/IAM/latest/UserGuide/id_roles_terms-and-concepts.html#term_trust-policy
+ */
+type TrustPolicy struct {
+	PolicyDocument *PolicyDocument
+}
+
+func (this TrustPolicy) String() string {
+	return "TrustPolicy{PolicyDocument: " + this.PolicyDocument.String() + "}"
+}
+
+/**
+ * Parses a trust policy, a policy document such as {"Version": "2012-10-17", "Statement": [...]}.
+ *
+ * Errors are *ParseError values (see parseerror.go) with paths such as "/Statement/0/Action/1".
+ * Only the first error is returned, see Validate for getting all of them.
+ */
+func (policy *TrustPolicy) UnmarshalJSON(data []byte) error {
+	return firstError(checkSource(data, policy.unmarshal(data)))
+}
+
+/**
+ * Returns every error of the trust policy instead of only the first one, e.g. all the statements granting an action
+ * other than sts:AssumeRole* and sts:TagSession. Returns nil if the policy is valid.
+ */
+func (policy *TrustPolicy) Validate(data []byte) []*ParseError {
+	return toParseErrors(checkSource(data, policy.unmarshal(data)))
+}
+
+// Parses the policy, returning every error found
+func (policy *TrustPolicy) unmarshal(data []byte) []error {
+	return unmarshalDocument(data, &policy.PolicyDocument, PolicyKindTrust, nil)
+}
+
+/**
+ * Writes the trust policy back as the document it was parsed from, e.g. for the AssumeRolePolicyDocument of a role.
+ */
+func (policy TrustPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(policy.PolicyDocument)
+}
+
+// Whether the action only covers actions a trust policy may grant, e.g. "sts:AssumeRole*" but not "sts:*"
+func isTrustAction(action Action) bool {
+	if !strings.EqualFold(action.Service, "sts") {
+		return false
+	}
+	name := strings.ToLower(action.Name)
+	return strings.HasPrefix(name, "assumerole") || name == "tagsession"
+}
+
+// Checks the Resource and Action elements of the statements of a trust policy
+func (pd *PolicyDocument) trustErrors() []error {
+	var errs []error
+	for i, statement := range pd.StatementList() {
+		if statement.ResourceValue != nil {
+			key := "Resource"
+			if !statement.Resource {
+				key = "NotResource"
+			}
+			errs = append(errs, newParseError(CodeInvalidResource, pd.statementPath(i)+"/"+key, fmt.Sprintf("%s is not allowed in a trust policy", key)))
+		}
+		if statement.ActionValue == nil {
+			continue
+		}
+		if !statement.Action {
+			errs = append(errs, newParseError(CodeInvalidAction, pd.statementPath(i)+"/NotAction", "NotAction is not allowed in a trust policy"))
+			continue
+		}
+		for j, actionString := range statement.actionStrings() {
+			action, err := ParseAction(actionString)
+			// invalid actions are already reported by the statement
+			if err != nil || isTrustAction(action) {
+				continue
+			}
+			path := pd.statementPath(i) + elementPath("Action", statement.ActionValue, j)
+			errs = append(errs, newParseError(CodeInvalidAction, path, fmt.Sprintf(`action "%s" is not allowed in a trust policy, it should be sts:AssumeRole, sts:AssumeRoleWithSAML, sts:AssumeRoleWithWebIdentity or sts:TagSession`, actionString)))
+		}
+	}
+	return errs
+}
+
+/**
+ * Returns a finding for every "Allow" statement that lets anyone assume the role, i.e. whose principal is "*"
+ * (or {"AWS": "*"}) or a NotPrincipal, and that has no condition to narrow it down.
+ * The findings flag the statement as a whole, Value being "Principal" or "NotPrincipal".
+ */
+func (policy TrustPolicy) StatementsWithPublicTrust() []StatementFinding {
+	var findings []StatementFinding
+	for i, statement := range allowStatements(policy.PolicyDocument.StatementList()) {
+		if statement.Principals == nil || len(statement.Conditions) > 0 {
+			continue
+		}
+		if statement.Principals.NotPrincipal {
+			findings = append(findings, StatementFinding{StatementIndex: i, Sid: statement.Sid, ElementIndex: -1, Value: "NotPrincipal"})
+		} else if statement.Principals.Wildcard {
+			findings = append(findings, StatementFinding{StatementIndex: i, Sid: statement.Sid, ElementIndex: -1, Value: "Principal"})
+		}
+	}
+	return findings
+}
+
+/**
+ * Returns a finding for every "AWS" principal of an "Allow" statement that belongs to another account than
+ * the given one (the account of the role), if the statement has no sts:ExternalId condition.
+ * ElementIndex is the index of the principal in the "AWS" list.
+ *
+ * see https://docs.aws.amazon.com/IAM/latest/UserGuide/id_roles_create_for-user_externalid.html
+ */
+func (policy TrustPolicy) StatementsWithCrossAccountTrustWithoutExternalId(account string) []StatementFinding {
+	var findings []StatementFinding
+	for i, statement := range allowStatements(policy.PolicyDocument.StatementList()) {
+		if statement.Principals == nil || statement.Principals.NotPrincipal || statement.Conditions.hasKey("sts:ExternalId") {
+			continue
+		}
+		for j, id := range statement.Principals.AWS {
+			principalAccount := id
+			if arn, err := ParseARN(id); err == nil {
+				principalAccount = arn.Account
+			}
+			if isAccountId(principalAccount) && principalAccount != account {
+				findings = append(findings, StatementFinding{StatementIndex: i, Sid: statement.Sid, ElementIndex: j, Value: id})
+			}
+		}
+	}
+	return findings
+}
+
+/**
+ * Returns a finding for every OIDC "Federated" principal of an "Allow" statement (an OIDC provider ARN or a
+ * well-known provider such as "accounts.google.com") that the statement doesn't narrow down with a condition on
+ * the audience or the subject of the token (e.g. "token.actions.githubusercontent.com:sub", "graph.facebook.com:app_id").
+ * Without one, anyone with a token of the provider can assume the role.
+ * ElementIndex is the index of the principal in the "Federated" list.
+ */
+func (policy TrustPolicy) StatementsWithOidcTrustWithoutAudienceOrSubject() []StatementFinding {
+	var findings []StatementFinding
+	for i, statement := range allowStatements(policy.PolicyDocument.StatementList()) {
+		if statement.Principals == nil || statement.Principals.NotPrincipal {
+			continue
+		}
+		for j, id := range statement.Principals.Federated {
+			provider, ok := oidcProvider(id)
+			if !ok {
+				continue
+			}
+			keys := oidcTokenKeys[provider]
+			if keys == [2]string{} {
+				keys = [2]string{"aud", "sub"}
+			}
+			if statement.Conditions.hasKey(provider+":"+keys[0]) || statement.Conditions.hasKey(provider+":"+keys[1]) {
+				continue
+			}
+			findings = append(findings, StatementFinding{StatementIndex: i, Sid: statement.Sid, ElementIndex: j, Value: id})
+		}
+	}
+	return findings
+}
+
+// The condition keys holding the audience and the subject of a token, for the providers not using "aud" and "sub"
+// see https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_iam-condition-keys.html#condition-keys-wif
+var oidcTokenKeys = map[string][2]string{
+	"www.amazon.com":     {"app_id", "user_id"},
+	"graph.facebook.com": {"app_id", "id"},
+}
+
+// Returns the name an OIDC provider is known by in condition keys, e.g. "token.actions.githubusercontent.com" for
+// "arn:aws:iam::123456789012:oidc-provider/token.actions.githubusercontent.com", false if the principal is not an OIDC provider
+func oidcProvider(federated string) (string, bool) {
+	if containsString(wellKnownIdentityProviders, federated) {
+		return federated, true
+	}
+	arn, err := ParseARN(federated)
+	if err != nil || arn.ResourceType != "oidc-provider" {
+		return "", false
+	}
+	return arn.ResourceId, true
+}
@@ -0,0 +1,132 @@
+package iamrolepolicyparsing
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const githubOidcProvider = "arn:aws:iam::123456789012:oidc-provider/token.actions.githubusercontent.com"
+
+func TestTrustPolicy_Unmarshal(t *testing.T) {
+	data := `{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Principal": {"Service": ["ec2.amazonaws.com"]}, "Action": "sts:AssumeRole"},
+		{"Effect": "Allow", "Principal": {"Federated": ["` + githubOidcProvider + `"]}, "Action": ["sts:AssumeRoleWithWebIdentity", "sts:TagSession"],
+			"Condition": {"StringLike": {"token.actions.githubusercontent.com:sub": "repo:org/repo:*"}}}
+	]}`
+	var trustPolicy TrustPolicy
+
+	err := json.Unmarshal([]byte(data), &trustPolicy)
+
+	if err != nil {
+		t.Errorf("Expected error: <nil>, got: %v", err)
+	}
+	if len(trustPolicy.PolicyDocument.StatementList()) != 2 {
+		t.Errorf("Expected 2 statements, got: %v", trustPolicy)
+	}
+}
+
+func TestTrustPolicy_Validate(t *testing.T) {
+	data := `{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Action": "sts:AssumeRole"},
+		{"Effect": "Allow", "Principal": {"AWS": ["123456789012"]}, "Action": ["sts:AssumeRole*", "sts:*", "*"], "Resource": "*"},
+		{"Effect": "Allow", "Principal": "*", "NotAction": "s3:*"}
+	]}`
+	var trustPolicy TrustPolicy
+	expected := []string{
+		"/Statement/0/Principal: Principal or NotPrincipal is required in a trust policy",
+		"/Statement/1/Resource: Resource is not allowed in a trust policy",
+		`/Statement/1/Action/1: action "sts:*" is not allowed in a trust policy, it should be sts:AssumeRole, sts:AssumeRoleWithSAML, sts:AssumeRoleWithWebIdentity or sts:TagSession`,
+		`/Statement/1/Action/2: action "*" is not allowed in a trust policy, it should be sts:AssumeRole, sts:AssumeRoleWithSAML, sts:AssumeRoleWithWebIdentity or sts:TagSession`,
+		"/Statement/2/NotAction: NotAction is not allowed in a trust policy",
+	}
+
+	var messages []string
+	for _, parseError := range trustPolicy.Validate([]byte(data)) {
+		messages = append(messages, parseError.Error())
+	}
+
+	if !reflect.DeepEqual(messages, expected) {
+		t.Errorf("Expected: %q, got: %q", expected, messages)
+	}
+}
+
+func TestTrustPolicy_MarshalJSON(t *testing.T) {
+	data := `{"Version":"2012-10-17","Statement":{"Effect":"Allow","Principal":{"Service":"ec2.amazonaws.com"},"Action":"sts:AssumeRole"}}`
+	var trustPolicy TrustPolicy
+	if err := json.Unmarshal([]byte(data), &trustPolicy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+
+	marshalled, err := json.Marshal(trustPolicy)
+
+	if err != nil || string(marshalled) != data {
+		t.Errorf("Expected: %s, got: %s (error: %v)", data, marshalled, err)
+	}
+}
+
+func TestTrustPolicy_StatementsWithPublicTrust(t *testing.T) {
+	data := `{"Version": "2012-10-17", "Statement": [
+		{"Sid": "Public", "Effect": "Allow", "Principal": "*", "Action": "sts:AssumeRole"},
+		{"Effect": "Allow", "Principal": {"AWS": ["*"]}, "Action": "sts:AssumeRole", "Condition": {"StringEquals": {"aws:PrincipalOrgID": "o-123"}}},
+		{"Effect": "Allow", "NotPrincipal": {"AWS": ["123456789012"]}, "Action": "sts:AssumeRole"},
+		{"Effect": "Deny", "Principal": "*", "Action": "sts:AssumeRole"}
+	]}`
+	var trustPolicy TrustPolicy
+	if err := json.Unmarshal([]byte(data), &trustPolicy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+	expected := []StatementFinding{
+		{StatementIndex: 0, Sid: stringOf("Public"), ElementIndex: -1, Value: "Principal"},
+		{StatementIndex: 2, ElementIndex: -1, Value: "NotPrincipal"},
+	}
+
+	findings := trustPolicy.StatementsWithPublicTrust()
+
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, findings)
+	}
+}
+
+func TestTrustPolicy_StatementsWithCrossAccountTrustWithoutExternalId(t *testing.T) {
+	data := `{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Principal": {"AWS": ["111111111111", "arn:aws:iam::222222222222:role/Deployer", "arn:aws:iam::111111111111:root"]}, "Action": "sts:AssumeRole"},
+		{"Effect": "Allow", "Principal": {"AWS": ["333333333333"]}, "Action": "sts:AssumeRole", "Condition": {"StringEquals": {"sts:externalid": "secret"}}}
+	]}`
+	var trustPolicy TrustPolicy
+	if err := json.Unmarshal([]byte(data), &trustPolicy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+	expected := []StatementFinding{{StatementIndex: 0, ElementIndex: 1, Value: "arn:aws:iam::222222222222:role/Deployer"}}
+
+	findings := trustPolicy.StatementsWithCrossAccountTrustWithoutExternalId("111111111111")
+
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, findings)
+	}
+}
+
+func TestTrustPolicy_StatementsWithOidcTrustWithoutAudienceOrSubject(t *testing.T) {
+	data := `{"Version": "2012-10-17", "Statement": [
+		{"Effect": "Allow", "Principal": {"Federated": ["` + githubOidcProvider + `"]}, "Action": "sts:AssumeRoleWithWebIdentity"},
+		{"Effect": "Allow", "Principal": {"Federated": ["` + githubOidcProvider + `"]}, "Action": "sts:AssumeRoleWithWebIdentity",
+			"Condition": {"StringEquals": {"token.actions.githubusercontent.com:aud": "sts.amazonaws.com"}}},
+		{"Effect": "Allow", "Principal": {"Federated": ["graph.facebook.com", "accounts.google.com"]}, "Action": "sts:AssumeRoleWithWebIdentity",
+			"Condition": {"StringEquals": {"graph.facebook.com:app_id": "1234"}}},
+		{"Effect": "Allow", "Principal": {"Federated": ["arn:aws:iam::123456789012:saml-provider/Okta"]}, "Action": "sts:AssumeRoleWithSAML"}
+	]}`
+	var trustPolicy TrustPolicy
+	if err := json.Unmarshal([]byte(data), &trustPolicy); err != nil {
+		t.Fatalf("Expected error: <nil>, got: %v", err)
+	}
+	expected := []StatementFinding{
+		{StatementIndex: 0, ElementIndex: 0, Value: githubOidcProvider},
+		{StatementIndex: 2, ElementIndex: 1, Value: "accounts.google.com"},
+	}
+
+	findings := trustPolicy.StatementsWithOidcTrustWithoutAudienceOrSubject()
+
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Expected: %v, got: %v", expected, findings)
+	}
+}